@@ -0,0 +1,240 @@
+package chapter09
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned when putting into a closed BlockingQueue, or taking from one which is closed
+// and drained.
+var ErrClosed = errors.New("queue closed")
+
+// QueueMetrics is a snapshot of how a BlockingQueue has been used.
+type QueueMetrics struct {
+	// Depth is the number of items in the queue right now, and MaxDepth the most there have ever been.
+	Depth    int
+	MaxDepth int
+	Capacity int
+	Puts     uint64
+	Takes    uint64
+	// PutWait and TakeWait add up the time callers spent blocked because the queue was full or empty.
+	PutWait  time.Duration
+	TakeWait time.Duration
+	// PutWaiters and TakeWaiters are the number of callers blocked in Put and Take right now.
+	PutWaiters  int
+	TakeWaiters int
+}
+
+// BlockingQueue is a bounded first in, first out queue which is safe for concurrent use. Put waits while
+// the queue is full and Take waits while it's empty, both giving up when their context is done.
+//
+// After Close nothing can be put anymore, but the items already in the queue can still be taken.
+// Take only returns ErrClosed once the queue is empty as well.
+type BlockingQueue[T any] struct {
+	mu     sync.Mutex
+	items  *Deque[T]
+	closed bool
+	// notFull is signalled for every item taken and notEmpty for every item put, so only one waiter
+	// wakes up per item. Close wakes all of them.
+	notFull  *sync.Cond
+	notEmpty *sync.Cond
+	metrics  QueueMetrics
+}
+
+// NewBlockingQueue creates a queue which holds at most capacity items.
+func NewBlockingQueue[T any](capacity int) (*BlockingQueue[T], error) {
+	items, err := NewFixedDeque[T](capacity, Reject)
+	if err != nil {
+		return nil, err
+	}
+	q := &BlockingQueue[T]{
+		items:   items,
+		metrics: QueueMetrics{Capacity: capacity},
+	}
+	q.notFull = sync.NewCond(&q.mu)
+	q.notEmpty = sync.NewCond(&q.mu)
+	return q, nil
+}
+
+// wait blocks on cond until it's signalled or ctx is done, and returns the context's error in the
+// latter case. It has to be called with the lock held. A sync.Cond can't wait on a context, so a
+// goroutine wakes the waiters of cond once ctx is done.
+func (q *BlockingQueue[T]) wait(ctx context.Context, cond *sync.Cond) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ctx.Done() != nil {
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			select {
+			case <-ctx.Done():
+				q.mu.Lock()
+				cond.Broadcast()
+				q.mu.Unlock()
+			case <-stop:
+			}
+		}()
+	}
+	cond.Wait()
+	return ctx.Err()
+}
+
+// tryPut has to be called with the lock held.
+func (q *BlockingQueue[T]) tryPut(v T) error {
+	if q.closed {
+		return ErrClosed
+	}
+	if err := q.items.PushBack(v); err != nil {
+		return err
+	}
+	q.metrics.Puts++
+	if depth := q.items.Len(); depth > q.metrics.MaxDepth {
+		q.metrics.MaxDepth = depth
+	}
+	q.notEmpty.Signal()
+	return nil
+}
+
+// tryTake has to be called with the lock held.
+func (q *BlockingQueue[T]) tryTake() (T, error) {
+	v, err := q.items.PopFront()
+	if err != nil {
+		if q.closed {
+			return v, ErrClosed
+		}
+		return v, err
+	}
+	q.metrics.Takes++
+	q.notFull.Signal()
+	return v, nil
+}
+
+// Put adds an item to the end of the queue, waiting for room if it's full. It returns ErrClosed if the
+// queue is closed, or the context's error if the context is done first.
+func (q *BlockingQueue[T]) Put(ctx context.Context, v T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var start time.Time
+	for {
+		err := q.tryPut(v)
+		if !errors.Is(err, ErrFull) {
+			q.recordWait(&q.metrics.PutWait, start)
+			return err
+		}
+
+		if start.IsZero() {
+			start = time.Now()
+		}
+		q.metrics.PutWaiters++
+		err = q.wait(ctx, q.notFull)
+		q.metrics.PutWaiters--
+		if err != nil {
+			// This Put may have been woken for a free slot, which then goes to the next waiter instead.
+			if q.items.Len() < q.metrics.Capacity {
+				q.notFull.Signal()
+			}
+			q.recordWait(&q.metrics.PutWait, start)
+			return err
+		}
+	}
+}
+
+// Take removes the first item from the queue, waiting for one if it's empty. It returns ErrClosed if the
+// queue is closed and empty, or the context's error if the context is done first.
+func (q *BlockingQueue[T]) Take(ctx context.Context) (T, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var start time.Time
+	for {
+		v, err := q.tryTake()
+		if !errors.Is(err, ErrEmpty) {
+			q.recordWait(&q.metrics.TakeWait, start)
+			return v, err
+		}
+
+		if start.IsZero() {
+			start = time.Now()
+		}
+		q.metrics.TakeWaiters++
+		err = q.wait(ctx, q.notEmpty)
+		q.metrics.TakeWaiters--
+		if err != nil {
+			// This Take may have been woken for an item, which then goes to the next waiter instead.
+			if q.items.Len() > 0 {
+				q.notEmpty.Signal()
+			}
+			q.recordWait(&q.metrics.TakeWait, start)
+			return v, err
+		}
+	}
+}
+
+// recordWait adds the time since start to total, if the caller had to wait at all. It has to be called
+// with the lock held.
+func (q *BlockingQueue[T]) recordWait(total *time.Duration, start time.Time) {
+	if !start.IsZero() {
+		*total += time.Since(start)
+	}
+}
+
+// TryPut adds an item without waiting. It returns ErrFull if there is no room, or ErrClosed.
+func (q *BlockingQueue[T]) TryPut(v T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.tryPut(v)
+}
+
+// TryTake removes the first item without waiting. It returns ErrEmpty if there is none, or ErrClosed
+// if the queue is closed and empty.
+func (q *BlockingQueue[T]) TryTake() (T, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.tryTake()
+}
+
+// Close stops the queue from accepting items and wakes everyone waiting. Waiting Puts return ErrClosed,
+// while Takes carry on until the queue is drained. Closing twice does nothing.
+func (q *BlockingQueue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.notFull.Broadcast()
+	q.notEmpty.Broadcast()
+}
+
+// Drain removes and returns all items in the queue.
+func (q *BlockingQueue[T]) Drain() []T {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	items := q.items.Values()
+	q.items.Clear()
+	q.metrics.Takes += uint64(len(items))
+	if len(items) > 0 {
+		q.notFull.Broadcast()
+	}
+	return items
+}
+
+// Len returns the number of items in the queue.
+func (q *BlockingQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.Len()
+}
+
+// Metrics returns a snapshot of the queue's metrics.
+func (q *BlockingQueue[T]) Metrics() QueueMetrics {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	m := q.metrics
+	m.Depth = q.items.Len()
+	return m
+}
@@ -0,0 +1,194 @@
+package chapter09
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockingQueueTry(t *testing.T) {
+	_, err := NewBlockingQueue[int](0)
+	assert.Error(t, err)
+
+	q, err := NewBlockingQueue[int](2)
+	require.NoError(t, err)
+	_, err = q.TryTake()
+	assert.ErrorIs(t, err, ErrEmpty)
+	require.NoError(t, q.TryPut(1))
+	require.NoError(t, q.TryPut(2))
+	assert.ErrorIs(t, q.TryPut(3), ErrFull)
+
+	v, err := q.TryTake()
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 1, q.Len())
+	assert.Equal(t, QueueMetrics{Depth: 1, MaxDepth: 2, Capacity: 2, Puts: 2, Takes: 1}, q.Metrics())
+}
+
+// waitForWaiters waits until the given number of Puts and Takes are blocked on the queue.
+func waitForWaiters(t *testing.T, q *BlockingQueue[int], puts, takes int) {
+	require.Eventually(t, func() bool {
+		m := q.Metrics()
+		return m.PutWaiters == puts && m.TakeWaiters == takes
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestBlockingQueueWaits(t *testing.T) {
+	q, err := NewBlockingQueue[int](1)
+	require.NoError(t, err)
+	require.NoError(t, q.Put(context.Background(), 1))
+
+	// The second Put has to wait until there is room.
+	done := make(chan error)
+	go func() {
+		done <- q.Put(context.Background(), 2)
+	}()
+	waitForWaiters(t, q, 1, 0)
+	select {
+	case <-done:
+		t.Fatal("put didn't wait for room")
+	default:
+	}
+
+	v, err := q.Take(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+	require.NoError(t, <-done)
+
+	v, err = q.Take(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, v)
+
+	taken := make(chan int)
+	go func() {
+		v, err := q.Take(context.Background())
+		assert.NoError(t, err)
+		taken <- v
+	}()
+	waitForWaiters(t, q, 0, 1)
+	require.NoError(t, q.TryPut(3))
+	assert.Equal(t, 3, <-taken)
+
+	m := q.Metrics()
+	assert.Positive(t, m.PutWait)
+	assert.Positive(t, m.TakeWait)
+	assert.Zero(t, m.PutWaiters)
+	assert.Zero(t, m.TakeWaiters)
+}
+
+func TestBlockingQueueContext(t *testing.T) {
+	q, err := NewBlockingQueue[int](1)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = q.Take(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, q.TryPut(1))
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, q.Put(ctx, 2), context.Canceled)
+	// A cancelled context doesn't stop a Take which doesn't have to wait.
+	v, err := q.Take(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
+
+func TestBlockingQueueClose(t *testing.T) {
+	q, err := NewBlockingQueue[int](2)
+	require.NoError(t, err)
+	require.NoError(t, q.TryPut(1))
+	require.NoError(t, q.TryPut(2))
+
+	putErr := make(chan error)
+	go func() {
+		putErr <- q.Put(context.Background(), 3)
+	}()
+	waitForWaiters(t, q, 1, 0)
+	q.Close()
+	q.Close()
+	assert.ErrorIs(t, <-putErr, ErrClosed)
+	assert.ErrorIs(t, q.TryPut(4), ErrClosed)
+
+	// Items which were already in the queue can still be taken.
+	v, err := q.Take(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, []int{2}, q.Drain())
+	_, err = q.Take(context.Background())
+	assert.ErrorIs(t, err, ErrClosed)
+	_, err = q.TryTake()
+	assert.ErrorIs(t, err, ErrClosed)
+
+	// A waiting Take is woken by Close.
+	empty, err := NewBlockingQueue[int](1)
+	require.NoError(t, err)
+	takeErr := make(chan error)
+	go func() {
+		_, err := empty.Take(context.Background())
+		takeErr <- err
+	}()
+	waitForWaiters(t, empty, 0, 1)
+	empty.Close()
+	assert.ErrorIs(t, <-takeErr, ErrClosed)
+}
+
+func TestBlockingQueueConcurrent(t *testing.T) {
+	q, err := NewBlockingQueue[int](8)
+	require.NoError(t, err)
+
+	const producers, perProducer, consumers = 4, 500, 4
+	var producing sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		producing.Add(1)
+		go func(p int) {
+			defer producing.Done()
+			for i := 0; i < perProducer; i++ {
+				assert.NoError(t, q.Put(context.Background(), p*perProducer+i))
+			}
+		}(p)
+	}
+
+	var (
+		mu       sync.Mutex
+		received []int
+		taking   sync.WaitGroup
+	)
+	for c := 0; c < consumers; c++ {
+		taking.Add(1)
+		go func() {
+			defer taking.Done()
+			for {
+				v, err := q.Take(context.Background())
+				if err != nil {
+					assert.ErrorIs(t, err, ErrClosed)
+					return
+				}
+				mu.Lock()
+				received = append(received, v)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	producing.Wait()
+	q.Close()
+	taking.Wait()
+
+	sort.Ints(received)
+	require.Len(t, received, producers*perProducer)
+	for i, v := range received {
+		assert.Equal(t, i, v)
+	}
+	m := q.Metrics()
+	assert.Equal(t, uint64(producers*perProducer), m.Puts)
+	assert.Equal(t, m.Puts, m.Takes)
+	assert.LessOrEqual(t, m.MaxDepth, 8)
+	assert.Equal(t, 0, m.Depth)
+}
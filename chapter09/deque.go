@@ -0,0 +1,249 @@
+package chapter09
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrEmpty is returned when reading from an empty Deque.
+	ErrEmpty = errors.New("empty")
+	// ErrFull is returned when pushing to a full Deque which rejects values on overflow.
+	ErrFull = errors.New("full")
+)
+
+// Overflow decides what a fixed capacity Deque does when a value is pushed while it's full.
+type Overflow int
+
+const (
+	// Reject refuses the new value with ErrFull.
+	Reject Overflow = iota
+	// Overwrite drops the value at the other end to make room, so pushing to the back drops the front
+	// and the other way around.
+	Overwrite
+)
+
+// minDequeCapacity is the smallest buffer a growing Deque uses, so it doesn't resize all the time while
+// it's small.
+const minDequeCapacity = 8
+
+// Deque is a double-ended queue on a ring buffer. Values can be pushed and popped at both ends in O(1)
+// and read by index. Unlike re-slicing a slice, popped slots are reused, so a long-running queue
+// doesn't keep growing its backing array.
+//
+// By default the buffer doubles when it's full and halves when only a quarter of it is used. A Deque
+// created by NewFixedDeque never resizes and handles overflow as configured instead.
+type Deque[T any] struct {
+	buffer []T
+	// head is the position of the first value in buffer.
+	head   int
+	length int
+	fixed  bool
+	policy Overflow
+}
+
+// NewDeque creates an empty Deque which grows and shrinks as needed.
+func NewDeque[T any]() *Deque[T] {
+	return &Deque[T]{}
+}
+
+// NewFixedDeque creates an empty Deque which holds at most capacity values.
+func NewFixedDeque[T any](capacity int, policy Overflow) (*Deque[T], error) {
+	if capacity < 1 {
+		return nil, fmt.Errorf("capacity must be at least 1, got %d", capacity)
+	}
+	return &Deque[T]{
+		buffer: make([]T, capacity),
+		fixed:  true,
+		policy: policy,
+	}, nil
+}
+
+// DequeFromSlice creates a growing Deque holding the values in the same order.
+func DequeFromSlice[T any](values []T) *Deque[T] {
+	capacity := minDequeCapacity
+	for capacity < len(values) {
+		capacity *= 2
+	}
+	buffer := make([]T, capacity)
+	copy(buffer, values)
+	return &Deque[T]{buffer: buffer, length: len(values)}
+}
+
+// Len returns the number of values in the Deque.
+func (d *Deque[T]) Len() int {
+	return d.length
+}
+
+// Cap returns the number of values the Deque can hold before it has to grow.
+func (d *Deque[T]) Cap() int {
+	return len(d.buffer)
+}
+
+// index returns the position in buffer of the i-th value.
+func (d *Deque[T]) index(i int) int {
+	return (d.head + i) % len(d.buffer)
+}
+
+// resize moves the values to the start of a new buffer of the given capacity.
+func (d *Deque[T]) resize(capacity int) {
+	buffer := make([]T, capacity)
+	if d.length > 0 {
+		if end := d.head + d.length; end <= len(d.buffer) {
+			copy(buffer, d.buffer[d.head:end])
+		} else {
+			n := copy(buffer, d.buffer[d.head:])
+			copy(buffer[n:], d.buffer[:end-len(d.buffer)])
+		}
+	}
+	d.buffer = buffer
+	d.head = 0
+}
+
+// makeRoom makes sure there is space for one more value. It returns true if a value at the other end
+// has to be dropped first.
+func (d *Deque[T]) makeRoom() (bool, error) {
+	if d.length < len(d.buffer) {
+		return false, nil
+	}
+	if !d.fixed {
+		d.resize(maxInt(minDequeCapacity, 2*len(d.buffer)))
+		return false, nil
+	}
+	if d.policy == Overwrite {
+		return true, nil
+	}
+	return false, ErrFull
+}
+
+func (d *Deque[T]) shrink() {
+	if !d.fixed && len(d.buffer) > minDequeCapacity && d.length <= len(d.buffer)/4 {
+		d.resize(len(d.buffer) / 2)
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// PushBack adds a value to the end.
+func (d *Deque[T]) PushBack(v T) error {
+	overwrite, err := d.makeRoom()
+	if err != nil {
+		return err
+	}
+	if overwrite {
+		// The slot after the last one is the first one, which is the value being dropped.
+		d.buffer[d.head] = v
+		d.head = d.index(1)
+		return nil
+	}
+	d.buffer[d.index(d.length)] = v
+	d.length++
+	return nil
+}
+
+// PushFront adds a value to the start.
+func (d *Deque[T]) PushFront(v T) error {
+	overwrite, err := d.makeRoom()
+	if err != nil {
+		return err
+	}
+	d.head = d.index(len(d.buffer) - 1)
+	// When overwriting, the slot before the first one is the last one, which is the value being dropped.
+	d.buffer[d.head] = v
+	if !overwrite {
+		d.length++
+	}
+	return nil
+}
+
+// PopFront removes the first value and returns it.
+func (d *Deque[T]) PopFront() (T, error) {
+	var zero T
+	if d.length == 0 {
+		return zero, ErrEmpty
+	}
+	v := d.buffer[d.head]
+	// Clear the slot, so the Deque doesn't keep the value alive.
+	d.buffer[d.head] = zero
+	d.head = d.index(1)
+	d.length--
+	d.shrink()
+	return v, nil
+}
+
+// PopBack removes the last value and returns it.
+func (d *Deque[T]) PopBack() (T, error) {
+	var zero T
+	if d.length == 0 {
+		return zero, ErrEmpty
+	}
+	i := d.index(d.length - 1)
+	v := d.buffer[i]
+	d.buffer[i] = zero
+	d.length--
+	d.shrink()
+	return v, nil
+}
+
+// Front returns the first value without removing it.
+func (d *Deque[T]) Front() (T, error) {
+	return d.At(0)
+}
+
+// Back returns the last value without removing it.
+func (d *Deque[T]) Back() (T, error) {
+	return d.At(d.length - 1)
+}
+
+func (d *Deque[T]) checkIndex(i int) error {
+	if d.length == 0 {
+		return ErrEmpty
+	}
+	if i < 0 || i >= d.length {
+		return fmt.Errorf("index %d is out of range [0, %d)", i, d.length)
+	}
+	return nil
+}
+
+// At returns the i-th value counting from the front.
+func (d *Deque[T]) At(i int) (T, error) {
+	if err := d.checkIndex(i); err != nil {
+		var zero T
+		return zero, err
+	}
+	return d.buffer[d.index(i)], nil
+}
+
+// Set replaces the i-th value counting from the front.
+func (d *Deque[T]) Set(i int, v T) error {
+	if err := d.checkIndex(i); err != nil {
+		return err
+	}
+	d.buffer[d.index(i)] = v
+	return nil
+}
+
+// Clear removes all values. A growing Deque also gives its buffer back.
+func (d *Deque[T]) Clear() {
+	if d.fixed {
+		d.buffer = make([]T, len(d.buffer))
+	} else {
+		d.buffer = nil
+	}
+	d.head = 0
+	d.length = 0
+}
+
+// Values returns the values from front to back.
+func (d *Deque[T]) Values() []T {
+	values := make([]T, d.length)
+	for i := range values {
+		values[i] = d.buffer[d.index(i)]
+	}
+	return values
+}
@@ -0,0 +1,188 @@
+package chapter09
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeque(t *testing.T) {
+	d := NewDeque[int]()
+	_, err := d.PopFront()
+	assert.ErrorIs(t, err, ErrEmpty)
+	_, err = d.Back()
+	assert.ErrorIs(t, err, ErrEmpty)
+
+	require.NoError(t, d.PushBack(2))
+	require.NoError(t, d.PushFront(1))
+	require.NoError(t, d.PushBack(3))
+	assert.Equal(t, []int{1, 2, 3}, d.Values())
+
+	v, err := d.Front()
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+	v, err = d.Back()
+	require.NoError(t, err)
+	assert.Equal(t, 3, v)
+	require.NoError(t, d.Set(1, 20))
+	v, err = d.At(1)
+	require.NoError(t, err)
+	assert.Equal(t, 20, v)
+	_, err = d.At(3)
+	assert.EqualError(t, err, "index 3 is out of range [0, 3)")
+
+	v, err = d.PopBack()
+	require.NoError(t, err)
+	assert.Equal(t, 3, v)
+	v, err = d.PopFront()
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 1, d.Len())
+
+	d.Clear()
+	assert.Equal(t, 0, d.Len())
+	assert.Equal(t, []int{}, d.Values())
+}
+
+// TestDequeRandom compares a Deque with a slice over a lot of random operations, which makes the ring
+// buffer wrap around, grow and shrink.
+func TestDequeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	d := NewDeque[int]()
+	var expected []int
+	for i := 0; i < 10000; i++ {
+		// Lean towards pushing in the first half and popping in the second.
+		push := r.Intn(10) < 6
+		if i > 5000 {
+			push = !push
+		}
+		switch {
+		case push && r.Intn(2) == 0:
+			require.NoError(t, d.PushBack(i))
+			expected = append(expected, i)
+		case push:
+			require.NoError(t, d.PushFront(i))
+			expected = append([]int{i}, expected...)
+		case len(expected) == 0:
+			_, err := d.PopFront()
+			assert.ErrorIs(t, err, ErrEmpty)
+		case r.Intn(2) == 0:
+			v, err := d.PopBack()
+			require.NoError(t, err)
+			assert.Equal(t, expected[len(expected)-1], v)
+			expected = expected[:len(expected)-1]
+		default:
+			v, err := d.PopFront()
+			require.NoError(t, err)
+			assert.Equal(t, expected[0], v)
+			expected = expected[1:]
+		}
+
+		require.Equal(t, len(expected), d.Len())
+		assert.LessOrEqual(t, d.Len(), d.Cap())
+	}
+	assert.Equal(t, append([]int{}, expected...), d.Values())
+}
+
+func TestDequeGrowAndShrink(t *testing.T) {
+	d := NewDeque[int]()
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, d.PushBack(i))
+	}
+	assert.Equal(t, 1024, d.Cap())
+
+	for i := 0; i < 990; i++ {
+		v, err := d.PopFront()
+		require.NoError(t, err)
+		assert.Equal(t, i, v)
+	}
+	assert.Equal(t, 32, d.Cap())
+	assert.Equal(t, []int{990, 991, 992, 993, 994, 995, 996, 997, 998, 999}, d.Values())
+
+	// Popped slots are cleared, so nothing is kept alive by the buffer.
+	p := NewDeque[*int]()
+	x := 1
+	require.NoError(t, p.PushBack(&x))
+	_, err := p.PopFront()
+	require.NoError(t, err)
+	assert.Nil(t, p.buffer[0])
+}
+
+func TestFixedDequeReject(t *testing.T) {
+	_, err := NewFixedDeque[int](0, Reject)
+	assert.EqualError(t, err, "capacity must be at least 1, got 0")
+
+	d, err := NewFixedDeque[int](3, Reject)
+	require.NoError(t, err)
+	require.NoError(t, d.PushBack(1))
+	require.NoError(t, d.PushBack(2))
+	require.NoError(t, d.PushFront(0))
+	assert.ErrorIs(t, d.PushBack(3), ErrFull)
+	assert.ErrorIs(t, d.PushFront(-1), ErrFull)
+	assert.Equal(t, []int{0, 1, 2}, d.Values())
+
+	_, err = d.PopFront()
+	require.NoError(t, err)
+	require.NoError(t, d.PushBack(3))
+	assert.Equal(t, []int{1, 2, 3}, d.Values())
+	assert.Equal(t, 3, d.Cap())
+}
+
+func TestFixedDequeOverwrite(t *testing.T) {
+	d, err := NewFixedDeque[int](3, Overwrite)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, d.PushBack(i))
+	}
+	assert.Equal(t, []int{2, 3, 4}, d.Values())
+
+	require.NoError(t, d.PushFront(1))
+	assert.Equal(t, []int{1, 2, 3}, d.Values())
+	require.NoError(t, d.PushFront(0))
+	assert.Equal(t, []int{0, 1, 2}, d.Values())
+	assert.Equal(t, 3, d.Len())
+
+	d.Clear()
+	assert.Equal(t, 3, d.Cap())
+	require.NoError(t, d.PushBack(7))
+	assert.Equal(t, []int{7}, d.Values())
+}
+
+func TestQueue(t *testing.T) {
+	q := NewQueue([]int{1, 2})
+	q.Enqueue(3)
+	assert.Equal(t, 3, q.Len())
+
+	v, err := q.Read()
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+	for i := 1; i <= 3; i++ {
+		v, err := q.Dequeue()
+		require.NoError(t, err)
+		assert.Equal(t, i, v)
+	}
+	assert.True(t, q.Empty())
+	_, err = q.Dequeue()
+	assert.EqualError(t, err, "empty")
+	_, err = q.Read()
+	assert.ErrorIs(t, err, ErrEmpty)
+}
+
+func TestQueueZeroValue(t *testing.T) {
+	var q Queue[int]
+	assert.True(t, q.Empty())
+	_, err := q.Read()
+	assert.ErrorIs(t, err, ErrEmpty)
+
+	for i := 0; i < 20; i++ {
+		q.Enqueue(i)
+	}
+	for i := 0; i < 20; i++ {
+		v, err := q.Dequeue()
+		require.NoError(t, err)
+		assert.Equal(t, i, v)
+	}
+	assert.True(t, q.Empty())
+}
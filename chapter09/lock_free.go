@@ -0,0 +1,127 @@
+package chapter09
+
+import "sync/atomic"
+
+type lockFreeNode[T any] struct {
+	value T
+	next  atomic.Pointer[lockFreeNode[T]]
+}
+
+// LockFreeStack is a Treiber stack, which is safe for concurrent use without a lock. The top of the stack
+// is an atomic pointer, and Push and Pop swap it with compare-and-swap, trying again if another goroutine
+// changed it in between.
+//
+// The ABA problem of Treiber stacks doesn't come up in Go: a node can't be freed and reused at the same
+// address while a goroutine still holds a pointer to it.
+type LockFreeStack[T any] struct {
+	top atomic.Pointer[lockFreeNode[T]]
+}
+
+func NewLockFreeStack[T any]() *LockFreeStack[T] {
+	return &LockFreeStack[T]{}
+}
+
+// Push pushes a value on top of the stack.
+func (s *LockFreeStack[T]) Push(v T) {
+	node := &lockFreeNode[T]{value: v}
+	for {
+		top := s.top.Load()
+		node.next.Store(top)
+		if s.top.CompareAndSwap(top, node) {
+			return
+		}
+	}
+}
+
+// Pop pops the value on top of the stack.
+func (s *LockFreeStack[T]) Pop() (T, error) {
+	for {
+		top := s.top.Load()
+		if top == nil {
+			var v T
+			return v, ErrEmpty
+		}
+		if s.top.CompareAndSwap(top, top.next.Load()) {
+			return top.value, nil
+		}
+	}
+}
+
+// Empty reports whether the stack was empty at the time of the call.
+func (s *LockFreeStack[T]) Empty() bool {
+	return s.top.Load() == nil
+}
+
+// LockFreeQueue is a Michael-Scott queue, which is safe for any number of concurrent producers and
+// consumers without a lock. It's a linked list which always starts with a dummy node; Dequeue moves
+// the head onto the first real node, which then becomes the new dummy.
+//
+// Enqueue first links the new node after the tail and only then moves the tail. A goroutine which
+// finds the tail lagging behind helps move it forward instead of waiting, so no goroutine can block
+// the others.
+type LockFreeQueue[T any] struct {
+	head atomic.Pointer[lockFreeNode[T]]
+	tail atomic.Pointer[lockFreeNode[T]]
+}
+
+func NewLockFreeQueue[T any]() *LockFreeQueue[T] {
+	q := &LockFreeQueue[T]{}
+	dummy := &lockFreeNode[T]{}
+	q.head.Store(dummy)
+	q.tail.Store(dummy)
+	return q
+}
+
+// Enqueue pushes a value to the end of the queue.
+func (q *LockFreeQueue[T]) Enqueue(v T) {
+	node := &lockFreeNode[T]{value: v}
+	for {
+		tail := q.tail.Load()
+		next := tail.next.Load()
+		if tail != q.tail.Load() {
+			continue
+		}
+		if next != nil {
+			// The tail is lagging behind, help move it.
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if tail.next.CompareAndSwap(nil, node) {
+			// If this fails, someone else has already moved the tail.
+			q.tail.CompareAndSwap(tail, node)
+			return
+		}
+	}
+}
+
+// Dequeue pops an item from the front of the queue.
+func (q *LockFreeQueue[T]) Dequeue() (T, error) {
+	for {
+		head := q.head.Load()
+		tail := q.tail.Load()
+		next := head.next.Load()
+		if head != q.head.Load() {
+			continue
+		}
+		if next == nil {
+			var v T
+			return v, ErrEmpty
+		}
+		if head == tail {
+			// There is a node, but the tail hasn't caught up with it yet.
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		// The value stays in next, which becomes the dummy, until the next Dequeue. Clearing it would race
+		// with goroutines which read it just before losing the compare-and-swap.
+		v := next.value
+		if q.head.CompareAndSwap(head, next) {
+			return v, nil
+		}
+	}
+}
+
+// Empty reports whether the queue was empty at the time of the call.
+func (q *LockFreeQueue[T]) Empty() bool {
+	return q.head.Load().next.Load() == nil
+}
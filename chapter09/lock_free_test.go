@@ -0,0 +1,202 @@
+package chapter09
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockFreeStack(t *testing.T) {
+	s := NewLockFreeStack[int]()
+	assert.True(t, s.Empty())
+	_, err := s.Pop()
+	assert.ErrorIs(t, err, ErrEmpty)
+
+	for i := 0; i < 3; i++ {
+		s.Push(i)
+	}
+	for i := 2; i >= 0; i-- {
+		v, err := s.Pop()
+		require.NoError(t, err)
+		assert.Equal(t, i, v)
+	}
+	assert.True(t, s.Empty())
+}
+
+func TestLockFreeQueue(t *testing.T) {
+	q := NewLockFreeQueue[int]()
+	assert.True(t, q.Empty())
+	_, err := q.Dequeue()
+	assert.ErrorIs(t, err, ErrEmpty)
+
+	for i := 0; i < 3; i++ {
+		q.Enqueue(i)
+	}
+	assert.False(t, q.Empty())
+	for i := 0; i < 3; i++ {
+		v, err := q.Dequeue()
+		require.NoError(t, err)
+		assert.Equal(t, i, v)
+	}
+	assert.True(t, q.Empty())
+}
+
+// stress runs producers and consumers at the same time and checks every value comes out exactly once.
+// For the queue it also checks that the values of every single producer come out in order.
+func stress(t *testing.T, push func(int), pop func() (int, error), fifo bool) {
+	const producers, consumers, perProducer = 8, 8, 5000
+
+	var producing sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		producing.Add(1)
+		go func(p int) {
+			defer producing.Done()
+			for i := 0; i < perProducer; i++ {
+				push(p*perProducer + i)
+			}
+		}(p)
+	}
+
+	done := make(chan struct{})
+	results := make([][]int, consumers)
+	var consuming sync.WaitGroup
+	for c := 0; c < consumers; c++ {
+		consuming.Add(1)
+		go func(c int) {
+			defer consuming.Done()
+			for {
+				v, err := pop()
+				if err == nil {
+					results[c] = append(results[c], v)
+					continue
+				}
+				select {
+				case <-done:
+					// Everything is pushed, so one more pop tells whether anything is left.
+					if v, err := pop(); err == nil {
+						results[c] = append(results[c], v)
+						continue
+					}
+					return
+				default:
+				}
+			}
+		}(c)
+	}
+
+	producing.Wait()
+	close(done)
+	consuming.Wait()
+
+	seen := make([]bool, producers*perProducer)
+	for _, values := range results {
+		last := make(map[int]int)
+		for _, v := range values {
+			require.False(t, seen[v], "value %d came out twice", v)
+			seen[v] = true
+
+			producer := v / perProducer
+			if previous, ok := last[producer]; ok && fifo {
+				require.Less(t, previous, v, "values of producer %d came out of order", producer)
+			}
+			last[producer] = v
+		}
+	}
+	for v, ok := range seen {
+		require.True(t, ok, "value %d got lost", v)
+	}
+}
+
+func TestLockFreeStackStress(t *testing.T) {
+	s := NewLockFreeStack[int]()
+	stress(t, s.Push, s.Pop, false)
+	assert.True(t, s.Empty())
+}
+
+func TestLockFreeQueueStress(t *testing.T) {
+	q := NewLockFreeQueue[int]()
+	stress(t, q.Enqueue, q.Dequeue, true)
+	assert.True(t, q.Empty())
+}
+
+// mutexStack and mutexQueue guard the plain Stack and Queue with a mutex, to compare against.
+type mutexStack struct {
+	mu    sync.Mutex
+	stack *Stack[int]
+}
+
+func (s *mutexStack) Push(v int) {
+	s.mu.Lock()
+	s.stack.Push(v)
+	s.mu.Unlock()
+}
+
+func (s *mutexStack) Pop() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stack.Pop()
+}
+
+type mutexQueue struct {
+	mu    sync.Mutex
+	queue *Queue[int]
+}
+
+func (q *mutexQueue) Push(v int) {
+	q.mu.Lock()
+	q.queue.Enqueue(v)
+	q.mu.Unlock()
+}
+
+func (q *mutexQueue) Pop() (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Dequeue()
+}
+
+// benchmarkPushPop splits b.N push and pop pairs over the given number of goroutines.
+func benchmarkPushPop(b *testing.B, goroutines int, push func(int), pop func() (int, error)) {
+	var wg sync.WaitGroup
+	perGoroutine := b.N/goroutines + 1
+	b.ResetTimer()
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				push(i)
+				_, _ = pop()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkStacks(b *testing.B) {
+	for _, goroutines := range []int{1, 4, 16, 64} {
+		b.Run(fmt.Sprintf("lock-free/%d", goroutines), func(b *testing.B) {
+			s := NewLockFreeStack[int]()
+			benchmarkPushPop(b, goroutines, s.Push, s.Pop)
+		})
+		b.Run(fmt.Sprintf("mutex/%d", goroutines), func(b *testing.B) {
+			s := &mutexStack{stack: NewStack[int](nil)}
+			benchmarkPushPop(b, goroutines, s.Push, s.Pop)
+		})
+	}
+}
+
+func BenchmarkQueues(b *testing.B) {
+	for _, goroutines := range []int{1, 4, 16, 64} {
+		b.Run(fmt.Sprintf("lock-free/%d", goroutines), func(b *testing.B) {
+			q := NewLockFreeQueue[int]()
+			benchmarkPushPop(b, goroutines, q.Enqueue, q.Dequeue)
+		})
+		b.Run(fmt.Sprintf("mutex/%d", goroutines), func(b *testing.B) {
+			q := &mutexQueue{queue: NewQueue[int](nil)}
+			benchmarkPushPop(b, goroutines, q.Push, q.Pop)
+		})
+	}
+}
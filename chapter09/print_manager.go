@@ -1,18 +1,445 @@
 package chapter09
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
 
+// Printer prints a single job. A returned error makes the PrintManager retry the job.
+type Printer[T any] interface {
+	Print(ctx context.Context, job T) error
+}
+
+// WriterPrinter writes every job to an io.Writer, which is all the PrintManager used to do.
+type WriterPrinter[T any] struct {
+	W io.Writer
+}
+
+func (p WriterPrinter[T]) Print(_ context.Context, job T) error {
+	_, err := fmt.Fprintln(p.W, "now printing: ", job)
+	return err
+}
+
+// Priority decides which queued jobs are printed first. Jobs of the same priority are printed in the
+// order they were queued.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+	priorityLevels
+)
+
+// JobID identifies a job queued in a PrintManager.
+type JobID uint64
+
+// JobState is where a job is in its life.
+type JobState int
+
+const (
+	// JobQueued jobs are waiting for a worker.
+	JobQueued JobState = iota
+	// JobRunning jobs are being printed right now.
+	JobRunning
+	// JobRetrying jobs have failed and wait for their backoff to pass before they're queued again.
+	JobRetrying
+	// JobDone, JobFailed and JobCancelled are final; failed jobs ran out of attempts.
+	JobDone
+	JobFailed
+	JobCancelled
+)
+
+func (s JobState) String() string {
+	switch s {
+	case JobQueued:
+		return "queued"
+	case JobRunning:
+		return "running"
+	case JobRetrying:
+		return "retrying"
+	case JobDone:
+		return "done"
+	case JobFailed:
+		return "failed"
+	case JobCancelled:
+		return "cancelled"
+	}
+	return fmt.Sprintf("JobState(%d)", int(s))
+}
+
+var (
+	// ErrUnknownJob is returned for a JobID the PrintManager never handed out.
+	ErrUnknownJob = errors.New("unknown job")
+	// ErrNotCancellable is returned when cancelling a job which is already running or finished.
+	ErrNotCancellable = errors.New("job is not queued")
+	// ErrNotFinished is returned when forgetting a job which isn't done, failed or cancelled yet.
+	ErrNotFinished = errors.New("job is not finished")
+)
+
+// JobStatus is a snapshot of a job.
+type JobStatus[T any] struct {
+	ID       JobID
+	Job      T
+	Priority Priority
+	State    JobState
+	Attempts int
+	// Err is the error of the last failed attempt.
+	Err error
+}
+
+// PrintManagerOptions configures a PrintManager. Zero values pick the defaults.
+type PrintManagerOptions struct {
+	// Workers is the number of jobs printed at the same time. Defaults to 1.
+	Workers int
+	// MaxAttempts is how many times a job is tried before it fails. Defaults to 1, so nothing is retried.
+	MaxAttempts int
+	// Backoff is the wait before the first retry, which doubles for every retry after that up to
+	// MaxBackoff. Defaults to 100ms, and MaxBackoff to 10s.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+type printJob[T any] struct {
+	status JobStatus[T]
+}
+
+// PrintManager is a print spooler. Jobs are queued with a priority and printed by a pool of workers,
+// failed jobs are retried with exponential backoff, and queued jobs can be cancelled. It's safe for
+// concurrent use.
 type PrintManager[T any] struct {
-	queue *Queue[T]
+	printer Printer[T]
+	options PrintManagerOptions
+
+	mu sync.Mutex
+	// changed is signalled whenever a job is queued or finishes, or the workers have to stop.
+	changed *sync.Cond
+	// queues holds the queued jobs per priority. Cancelled jobs stay in there and are skipped when
+	// they come up, since only the ends of a Deque can be taken from.
+	queues  [priorityLevels]*Deque[*printJob[T]]
+	jobs    map[JobID]*printJob[T]
+	nextID  JobID
+	pending int
+	running bool
+	// ctx is the context of the running workers and stop cancels it.
+	ctx     context.Context
+	stop    context.CancelFunc
+	workers sync.WaitGroup
+}
+
+// NewPrintManager creates a PrintManager which prints with printer. A nil printer prints to stdout.
+func NewPrintManager[T any](printer Printer[T], options PrintManagerOptions) *PrintManager[T] {
+	if printer == nil {
+		printer = WriterPrinter[T]{W: os.Stdout}
+	}
+	if options.Workers < 1 {
+		options.Workers = 1
+	}
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 1
+	}
+	if options.Backoff <= 0 {
+		options.Backoff = 100 * time.Millisecond
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = 10 * time.Second
+	}
+
+	p := &PrintManager[T]{
+		printer: printer,
+		options: options,
+		jobs:    make(map[JobID]*printJob[T]),
+	}
+	p.changed = sync.NewCond(&p.mu)
+	for i := range p.queues {
+		p.queues[i] = NewDeque[*printJob[T]]()
+	}
+	return p
+}
+
+// QueuePrintJob queues a job with normal priority.
+func (p *PrintManager[T]) QueuePrintJob(job T) JobID {
+	return p.Submit(job, PriorityNormal)
+}
+
+// Submit queues a job with the given priority and returns its ID.
+func (p *PrintManager[T]) Submit(job T, priority Priority) JobID {
+	if priority < PriorityLow {
+		priority = PriorityLow
+	}
+	if priority > PriorityHigh {
+		priority = PriorityHigh
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.nextID++
+	j := &printJob[T]{status: JobStatus[T]{ID: p.nextID, Job: job, Priority: priority, State: JobQueued}}
+	p.jobs[j.status.ID] = j
+	p.pending++
+	p.enqueue(j)
+	return j.status.ID
+}
+
+// enqueue has to be called with the lock held.
+func (p *PrintManager[T]) enqueue(j *printJob[T]) {
+	j.status.State = JobQueued
+	// A growing Deque never rejects a value.
+	_ = p.queues[j.status.Priority].PushBack(j)
+	p.changed.Broadcast()
+}
+
+// next returns the queued job with the highest priority, or nil if there is none. It has to be called
+// with the lock held.
+func (p *PrintManager[T]) next() *printJob[T] {
+	for priority := PriorityHigh; priority >= PriorityLow; priority-- {
+		for p.queues[priority].Len() > 0 {
+			j, _ := p.queues[priority].PopFront()
+			if j.status.State == JobQueued {
+				return j
+			}
+		}
+	}
+	return nil
+}
+
+// finish puts a job in a final state. It has to be called with the lock held.
+func (p *PrintManager[T]) finish(j *printJob[T], state JobState) {
+	j.status.State = state
+	p.pending--
+	p.changed.Broadcast()
+}
+
+// Start starts the workers. They keep printing jobs as they come in until ctx is done or Stop is called.
+// Starting a running PrintManager does nothing.
+func (p *PrintManager[T]) Start(ctx context.Context) {
+	p.start(ctx)
 }
 
-func (p *PrintManager[T]) QueuePrintJob(job T) {
-	p.queue.Enqueue(job)
+// start starts the workers and returns false if they were already running.
+func (p *PrintManager[T]) start(ctx context.Context) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.isRunning() {
+		return false
+	}
+	if p.stop != nil {
+		// The context was cancelled, but the watcher below didn't get to clean up yet.
+		p.stop()
+	}
+	p.running = true
+	ctx, p.stop = context.WithCancel(ctx)
+	p.ctx = ctx
+
+	for i := 0; i < p.options.Workers; i++ {
+		p.workers.Add(1)
+		go p.work(ctx)
+	}
+	// Wake the workers when the context is done, since they wait on the condition and not on ctx. If the
+	// context was cancelled by the caller instead of by Stop, the workers are gone, so the PrintManager
+	// isn't running anymore and can be started again.
+	go func() {
+		<-ctx.Done()
+		p.mu.Lock()
+		if p.ctx == ctx {
+			p.running = false
+			p.ctx, p.stop = nil, nil
+		}
+		p.changed.Broadcast()
+		p.mu.Unlock()
+	}()
+	return true
+}
+
+// isRunning returns true if the workers are running. They stop as soon as their context is done, even
+// before the watcher in start notices it. It has to be called with the lock held.
+func (p *PrintManager[T]) isRunning() bool {
+	return p.running && p.ctx.Err() == nil
+}
+
+func (p *PrintManager[T]) work(ctx context.Context) {
+	defer p.workers.Done()
+	for {
+		p.mu.Lock()
+		j := p.next()
+		for j == nil && ctx.Err() == nil {
+			p.changed.Wait()
+			j = p.next()
+		}
+		if ctx.Err() != nil {
+			if j != nil {
+				// Put it back in front of its queue, so it isn't lost or moved behind later jobs.
+				p.requeueFront(j)
+			}
+			p.mu.Unlock()
+			return
+		}
+		j.status.State = JobRunning
+		j.status.Attempts++
+		p.mu.Unlock()
+
+		err := p.printer.Print(ctx, j.status.Job)
+		p.done(ctx, j, err)
+	}
+}
+
+// requeueFront puts a job back at the front of its queue. It has to be called with the lock held.
+func (p *PrintManager[T]) requeueFront(j *printJob[T]) {
+	j.status.State = JobQueued
+	_ = p.queues[j.status.Priority].PushFront(j)
+	p.changed.Broadcast()
+}
+
+func (p *PrintManager[T]) done(ctx context.Context, j *printJob[T], err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	j.status.Err = err
+	switch {
+	case err == nil:
+		p.finish(j, JobDone)
+	case ctx.Err() != nil:
+		// The print failed because the workers are stopping, which doesn't count as an attempt. The job
+		// goes back in the queue for the next Start.
+		j.status.Attempts--
+		p.requeueFront(j)
+	case j.status.Attempts >= p.options.MaxAttempts:
+		p.finish(j, JobFailed)
+	default:
+		j.status.State = JobRetrying
+		time.AfterFunc(p.backoff(j.status.Attempts), func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			// The job may have been cancelled in the meantime.
+			if j.status.State == JobRetrying {
+				p.enqueue(j)
+			}
+		})
+	}
+}
+
+// backoff returns how long to wait after the given number of failed attempts.
+func (p *PrintManager[T]) backoff(attempts int) time.Duration {
+	d := p.options.Backoff
+	for i := 1; i < attempts && d < p.options.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.options.MaxBackoff {
+		d = p.options.MaxBackoff
+	}
+	return d
+}
+
+// Stop stops the workers and waits for them. Jobs being printed are interrupted through their context
+// and queued again, so a later Start picks everything up where it was left.
+func (p *PrintManager[T]) Stop() {
+	p.mu.Lock()
+	if p.running {
+		p.running = false
+		p.stop()
+		p.ctx, p.stop = nil, nil
+		p.changed.Broadcast()
+	}
+	p.mu.Unlock()
+
+	// Workers whose context was cancelled may still be finishing, so this waits even if not running.
+	p.workers.Wait()
 }
 
+// Wait blocks until every job submitted so far is done, failed or cancelled. Nothing gets printed while
+// the workers aren't running, so it returns right away if they aren't, or once they are stopped.
+func (p *PrintManager[T]) Wait() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for p.pending > 0 && p.isRunning() {
+		p.changed.Wait()
+	}
+}
+
+// Run prints every queued job and returns once they're all finished. If the PrintManager was started
+// before, it's left running, otherwise the workers Run started are stopped again.
 func (p *PrintManager[T]) Run() {
-	for !p.queue.Empty() {
-		v, _ := p.queue.Dequeue()
-		fmt.Println("now printing: ", v)
+	if p.start(context.Background()) {
+		defer p.Stop()
+	}
+	p.Wait()
+}
+
+// Cancel cancels a job which is queued or waiting to be retried. Running and finished jobs can't be
+// cancelled anymore.
+func (p *PrintManager[T]) Cancel(id JobID) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	j, ok := p.jobs[id]
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownJob, id)
+	}
+	if j.status.State != JobQueued && j.status.State != JobRetrying {
+		return fmt.Errorf("%w: job %d is %s", ErrNotCancellable, id, j.status.State)
+	}
+	p.finish(j, JobCancelled)
+	return nil
+}
+
+// Forget drops a finished job, so the PrintManager doesn't keep every job it ever printed. Its ID is
+// unknown afterwards.
+func (p *PrintManager[T]) Forget(id JobID) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	j, ok := p.jobs[id]
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownJob, id)
+	}
+	if j.status.State < JobDone {
+		return fmt.Errorf("%w: job %d is %s", ErrNotFinished, id, j.status.State)
+	}
+	delete(p.jobs, id)
+	return nil
+}
+
+// Status returns the status of a single job.
+func (p *PrintManager[T]) Status(id JobID) (JobStatus[T], error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	j, ok := p.jobs[id]
+	if !ok {
+		return JobStatus[T]{}, fmt.Errorf("%w: %d", ErrUnknownJob, id)
+	}
+	return j.status, nil
+}
+
+// Jobs returns the status of every job in one of the given states, or of every job if no state is
+// given, in the order they were submitted.
+func (p *PrintManager[T]) Jobs(states ...JobState) []JobStatus[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := []JobStatus[T]{}
+	for _, j := range p.jobs {
+		if len(states) == 0 || hasState(states, j.status.State) {
+			result = append(result, j.status)
+		}
+	}
+	sort.Slice(result, func(i, k int) bool {
+		return result[i].ID < result[k].ID
+	})
+	return result
+}
+
+func hasState(states []JobState, state JobState) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
 	}
+	return false
 }
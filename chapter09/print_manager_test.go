@@ -0,0 +1,252 @@
+package chapter09
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePrinter records what it printed. Jobs listed in failures fail that many times before they print,
+// and while block is set every print waits for it to be closed.
+type fakePrinter struct {
+	mu       sync.Mutex
+	printed  []string
+	failures map[string]int
+	block    chan struct{}
+	started  chan string
+}
+
+func (f *fakePrinter) Print(ctx context.Context, job string) error {
+	if f.started != nil {
+		f.started <- job
+	}
+	if f.block != nil {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failures[job] > 0 {
+		f.failures[job]--
+		return errors.New("paper jam")
+	}
+	f.printed = append(f.printed, job)
+	return nil
+}
+
+func (f *fakePrinter) Printed() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.printed...)
+}
+
+func TestPrintManagerRun(t *testing.T) {
+	var out bytes.Buffer
+	p := NewPrintManager[string](WriterPrinter[string]{W: &out}, PrintManagerOptions{})
+	p.QueuePrintJob("a")
+	p.QueuePrintJob("b")
+	p.Run()
+	assert.Equal(t, "now printing:  a\nnow printing:  b\n", out.String())
+
+	jobs := p.Jobs()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, JobStatus[string]{ID: 1, Job: "a", Priority: PriorityNormal, State: JobDone, Attempts: 1}, jobs[0])
+}
+
+func TestPrintManagerPriorities(t *testing.T) {
+	printer := &fakePrinter{}
+	p := NewPrintManager[string](printer, PrintManagerOptions{})
+	p.Submit("low", PriorityLow)
+	p.Submit("normal 1", PriorityNormal)
+	p.Submit("high", PriorityHigh)
+	p.Submit("normal 2", PriorityNormal)
+	p.Submit("urgent", Priority(10))
+	p.Run()
+	assert.Equal(t, []string{"high", "urgent", "normal 1", "normal 2", "low"}, printer.Printed())
+}
+
+func TestPrintManagerRetry(t *testing.T) {
+	printer := &fakePrinter{failures: map[string]int{"flaky": 2, "broken": 5}}
+	p := NewPrintManager[string](printer, PrintManagerOptions{
+		Workers:     2,
+		MaxAttempts: 3,
+		Backoff:     time.Millisecond,
+	})
+	flaky := p.QueuePrintJob("flaky")
+	broken := p.QueuePrintJob("broken")
+	p.QueuePrintJob("fine")
+	p.Run()
+
+	assert.ElementsMatch(t, []string{"flaky", "fine"}, printer.Printed())
+	status, err := p.Status(flaky)
+	require.NoError(t, err)
+	assert.Equal(t, JobDone, status.State)
+	assert.Equal(t, 3, status.Attempts)
+	assert.NoError(t, status.Err)
+
+	status, err = p.Status(broken)
+	require.NoError(t, err)
+	assert.Equal(t, JobFailed, status.State)
+	assert.Equal(t, 3, status.Attempts)
+	assert.EqualError(t, status.Err, "paper jam")
+	assert.Len(t, p.Jobs(JobDone, JobFailed), 3)
+}
+
+func TestPrintManagerBackoff(t *testing.T) {
+	p := NewPrintManager[string](nil, PrintManagerOptions{Backoff: time.Second, MaxBackoff: 5 * time.Second})
+	assert.Equal(t, time.Second, p.backoff(1))
+	assert.Equal(t, 2*time.Second, p.backoff(2))
+	assert.Equal(t, 4*time.Second, p.backoff(3))
+	assert.Equal(t, 5*time.Second, p.backoff(4))
+	assert.Equal(t, 5*time.Second, p.backoff(40))
+}
+
+func TestPrintManagerCancel(t *testing.T) {
+	printer := &fakePrinter{block: make(chan struct{}), started: make(chan string, 10)}
+	p := NewPrintManager[string](printer, PrintManagerOptions{})
+	first := p.QueuePrintJob("first")
+	second := p.QueuePrintJob("second")
+	third := p.QueuePrintJob("third")
+
+	p.Start(context.Background())
+	defer p.Stop()
+	assert.Equal(t, "first", <-printer.started)
+
+	assert.ErrorIs(t, p.Cancel(first), ErrNotCancellable)
+	require.NoError(t, p.Cancel(second))
+	assert.ErrorIs(t, p.Cancel(second), ErrNotCancellable)
+	assert.ErrorIs(t, p.Cancel(42), ErrUnknownJob)
+	_, err := p.Status(42)
+	assert.ErrorIs(t, err, ErrUnknownJob)
+
+	running := p.Jobs(JobRunning)
+	require.Len(t, running, 1)
+	assert.Equal(t, first, running[0].ID)
+	queued := p.Jobs(JobQueued)
+	require.Len(t, queued, 1)
+	assert.Equal(t, third, queued[0].ID)
+
+	close(printer.block)
+	p.Wait()
+	assert.Equal(t, []string{"first", "third"}, printer.Printed())
+	status, err := p.Status(second)
+	require.NoError(t, err)
+	assert.Equal(t, JobCancelled, status.State)
+	assert.Equal(t, 0, status.Attempts)
+}
+
+func TestPrintManagerCancelRetrying(t *testing.T) {
+	printer := &fakePrinter{failures: map[string]int{"flaky": 1}}
+	p := NewPrintManager[string](printer, PrintManagerOptions{MaxAttempts: 2, Backoff: time.Hour})
+	id := p.QueuePrintJob("flaky")
+	p.Start(context.Background())
+	defer p.Stop()
+
+	require.Eventually(t, func() bool {
+		status, _ := p.Status(id)
+		return status.State == JobRetrying
+	}, time.Second, time.Millisecond)
+	require.NoError(t, p.Cancel(id))
+	p.Wait()
+	assert.Empty(t, printer.Printed())
+}
+
+func TestPrintManagerStop(t *testing.T) {
+	printer := &fakePrinter{block: make(chan struct{}), started: make(chan string, 10)}
+	p := NewPrintManager[string](printer, PrintManagerOptions{Workers: 2})
+	p.Submit("a", PriorityHigh)
+	p.Submit("b", PriorityHigh)
+	p.Submit("c", PriorityHigh)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.Start(ctx)
+	<-printer.started
+	<-printer.started
+	cancel()
+	p.Stop()
+
+	// The interrupted jobs are queued again without using up an attempt.
+	for _, status := range p.Jobs() {
+		assert.Equal(t, JobQueued, status.State)
+		assert.Equal(t, 0, status.Attempts)
+	}
+
+	close(printer.block)
+	printer.started = nil
+	p.Run()
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, printer.Printed())
+}
+
+func TestPrintManagerRunKeepsStartedWorkers(t *testing.T) {
+	printer := &fakePrinter{}
+	p := NewPrintManager[string](printer, PrintManagerOptions{})
+	p.Start(context.Background())
+	defer p.Stop()
+
+	p.QueuePrintJob("a")
+	p.Run()
+	// Run didn't start the workers, so it leaves them running for later jobs.
+	id := p.QueuePrintJob("b")
+	require.Eventually(t, func() bool {
+		status, _ := p.Status(id)
+		return status.State == JobDone
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"a", "b"}, printer.Printed())
+}
+
+func TestPrintManagerWaitNotRunning(t *testing.T) {
+	p := NewPrintManager[string](&fakePrinter{}, PrintManagerOptions{})
+	id := p.QueuePrintJob("a")
+	// Nobody would ever print the job, so Wait must not block.
+	p.Wait()
+	status, err := p.Status(id)
+	require.NoError(t, err)
+	assert.Equal(t, JobQueued, status.State)
+}
+
+func TestPrintManagerForget(t *testing.T) {
+	printer := &fakePrinter{}
+	p := NewPrintManager[string](printer, PrintManagerOptions{})
+	queued := p.QueuePrintJob("queued")
+	assert.ErrorIs(t, p.Forget(queued), ErrNotFinished)
+	assert.ErrorIs(t, p.Forget(42), ErrUnknownJob)
+
+	p.Run()
+	require.NoError(t, p.Forget(queued))
+	assert.ErrorIs(t, p.Forget(queued), ErrUnknownJob)
+	_, err := p.Status(queued)
+	assert.ErrorIs(t, err, ErrUnknownJob)
+	assert.Empty(t, p.Jobs())
+}
+
+func TestPrintManagerStartContextCancelled(t *testing.T) {
+	printer := &fakePrinter{}
+	p := NewPrintManager[string](printer, PrintManagerOptions{})
+	ctx, cancel := context.WithCancel(context.Background())
+	p.Start(ctx)
+	cancel()
+
+	// The workers are gone with the context, so Run has to start new ones instead of waiting forever.
+	p.QueuePrintJob("a")
+	done := make(chan struct{})
+	go func() {
+		p.Run()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run didn't return")
+	}
+	assert.Equal(t, []string{"a"}, printer.Printed())
+}
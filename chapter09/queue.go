@@ -1,41 +1,38 @@
 package chapter09
 
-import "fmt"
-
+// Queue is a first in, first out queue backed by a growing Deque. The zero value is an empty Queue
+// ready to use.
 type Queue[T any] struct {
-	data []T
+	data Deque[T]
 }
 
 func NewQueue[T any](data []T) *Queue[T] {
 	return &Queue[T]{
-		data: data,
+		data: *DequeFromSlice(data),
 	}
 }
 
 // Enqueue pushes a value to the end of the Queue.
 func (s *Queue[T]) Enqueue(i T) {
-	s.data = append(s.data, i)
+	// A growing Deque never rejects a value.
+	_ = s.data.PushBack(i)
 }
 
 // Dequeue pops an item from the front of the Queue.
 func (s *Queue[T]) Dequeue() (T, error) {
-	var i T
-	if len(s.data) == 0 {
-		return i, fmt.Errorf("empty")
-	}
-	i, s.data = s.data[0], s.data[1:]
-	return i, nil
+	return s.data.PopFront()
 }
 
-// Read reads the last item in the Queue.
+// Read reads the first item in the Queue, which is the next one to be dequeued.
 func (s *Queue[T]) Read() (T, error) {
-	if len(s.data) == 0 {
-		var i T
-		return i, fmt.Errorf("empty")
-	}
-	return s.data[0], nil
+	return s.data.Front()
 }
 
 func (s *Queue[T]) Empty() bool {
-	return len(s.data) == 0
+	return s.data.Len() == 0
+}
+
+// Len returns the number of items in the Queue.
+func (s *Queue[T]) Len() int {
+	return s.data.Len()
 }
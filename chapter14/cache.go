@@ -0,0 +1,365 @@
+package chapter14
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// CacheStats counts how a cache has been used.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	// Expirations counts entries removed because their time to live ran out. They aren't counted
+	// as evictions.
+	Expirations uint64
+}
+
+type cacheEntry[K comparable, V any] struct {
+	value   V
+	expires time.Time
+	node    *DoubleNode[K]
+	// index is the position of the entry in the expiry heap.
+	index int
+}
+
+// expiryHeap orders the entries of a TTLCache by when they expire, so the one to expire first is
+// always on top. It implements heap.Interface.
+type expiryHeap[K comparable, V any] []*cacheEntry[K, V]
+
+func (h expiryHeap[K, V]) Len() int           { return len(h) }
+func (h expiryHeap[K, V]) Less(i, j int) bool { return h[i].expires.Before(h[j].expires) }
+
+func (h expiryHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap[K, V]) Push(x any) {
+	entry := x.(*cacheEntry[K, V])
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *expiryHeap[K, V]) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return entry
+}
+
+type evicted[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// lruCache is the part shared by LRUCache and TTLCache. It isn't safe for concurrent use on its own.
+// The list holds the keys from the most recently used to the least recently used, and the map points
+// at their nodes, so finding, moving and dropping an entry are all O(1).
+type lruCache[K comparable, V any] struct {
+	capacity int
+	items    map[K]*cacheEntry[K, V]
+	order    *DoublyLinkedList[K]
+	// expiry is only kept by the TTLCache, which makes a put or remove O(log n) there.
+	expiry *expiryHeap[K, V]
+	stats  CacheStats
+}
+
+func newLRUCache[K comparable, V any](capacity int) lruCache[K, V] {
+	return lruCache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*cacheEntry[K, V]),
+		order:    NewDoublyLinkedList[K](nil, nil),
+	}
+}
+
+// put stores the value and returns the entries evicted to make room for it.
+func (c *lruCache[K, V]) put(key K, value V, expires time.Time) []evicted[K, V] {
+	if entry, ok := c.items[key]; ok {
+		entry.value = value
+		entry.expires = expires
+		c.order.MoveToFront(entry.node)
+		if c.expiry != nil {
+			heap.Fix(c.expiry, entry.index)
+		}
+		return nil
+	}
+
+	var result []evicted[K, V]
+	if c.capacity > 0 && len(c.items) >= c.capacity {
+		key := c.order.LastNode.Data
+		result = append(result, evicted[K, V]{key: key, value: c.remove(key)})
+		c.stats.Evictions++
+	}
+	entry := &cacheEntry[K, V]{value: value, expires: expires, node: c.order.PushFront(key)}
+	c.items[key] = entry
+	if c.expiry != nil {
+		heap.Push(c.expiry, entry)
+	}
+	return result
+}
+
+func (c *lruCache[K, V]) remove(key K) V {
+	entry := c.items[key]
+	delete(c.items, key)
+	c.order.Remove(entry.node)
+	if c.expiry != nil {
+		heap.Remove(c.expiry, entry.index)
+	}
+	return entry.value
+}
+
+func (c *lruCache[K, V]) keys() []K {
+	result := c.order.Traverse()
+	if result == nil {
+		result = []K{}
+	}
+	return result
+}
+
+func notifyEvicted[K comparable, V any](onEvict func(key K, value V), entries []evicted[K, V]) {
+	if onEvict == nil {
+		return
+	}
+	for _, e := range entries {
+		onEvict(e.key, e.value)
+	}
+}
+
+// LRUCache keeps up to capacity entries and evicts the least recently used one to make room for a new
+// one. It's safe for concurrent use. The eviction callback is called after the cache is unlocked,
+// so it may use the cache itself.
+type LRUCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	cache   lruCache[K, V]
+	onEvict func(key K, value V)
+}
+
+// NewLRUCache creates a cache of the given capacity, where a capacity of 0 or less means the cache is
+// never full. onEvict is called for every entry evicted to make room, and may be nil.
+func NewLRUCache[K comparable, V any](capacity int, onEvict func(key K, value V)) *LRUCache[K, V] {
+	return &LRUCache[K, V]{
+		cache:   newLRUCache[K, V](capacity),
+		onEvict: onEvict,
+	}
+}
+
+// Get returns the value for key and marks it as the most recently used.
+func (c *LRUCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.cache.items[key]
+	if !ok {
+		c.cache.stats.Misses++
+		var v V
+		return v, false
+	}
+	c.cache.stats.Hits++
+	c.cache.order.MoveToFront(entry.node)
+	return entry.value, true
+}
+
+// Peek returns the value for key without marking it as used or counting it in the statistics.
+func (c *LRUCache[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.cache.items[key]
+	if !ok {
+		var v V
+		return v, false
+	}
+	return entry.value, true
+}
+
+// Put stores the value for key as the most recently used entry. It returns true if another entry had
+// to be evicted for it.
+func (c *LRUCache[K, V]) Put(key K, value V) bool {
+	c.mu.Lock()
+	entries := c.cache.put(key, value, time.Time{})
+	c.mu.Unlock()
+
+	notifyEvicted(c.onEvict, entries)
+	return len(entries) > 0
+}
+
+// Remove deletes key from the cache without calling the eviction callback. It returns false if the
+// key wasn't there.
+func (c *LRUCache[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.cache.items[key]; !ok {
+		return false
+	}
+	c.cache.remove(key)
+	return true
+}
+
+// Len returns the number of entries in the cache.
+func (c *LRUCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.cache.items)
+}
+
+// Keys returns the keys from the most recently used to the least recently used.
+func (c *LRUCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.keys()
+}
+
+// Stats returns the statistics of the cache so far.
+func (c *LRUCache[K, V]) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.stats
+}
+
+// TTLCache is an LRUCache where every entry also expires after a time to live. Expired entries are
+// dropped when they're found, or all at once with Purge. The eviction callback is called for expired
+// entries as well.
+type TTLCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	cache   lruCache[K, V]
+	ttl     time.Duration
+	now     func() time.Time
+	onEvict func(key K, value V)
+}
+
+// NewTTLCache creates a cache whose entries expire ttl after they were last put. Like for NewLRUCache, a
+// capacity of 0 or less means the cache is never full. now returns the current time and may be nil to
+// use time.Now; tests can pass a fake clock instead.
+func NewTTLCache[K comparable, V any](capacity int, ttl time.Duration, onEvict func(key K, value V), now func() time.Time) *TTLCache[K, V] {
+	if now == nil {
+		now = time.Now
+	}
+	cache := newLRUCache[K, V](capacity)
+	cache.expiry = &expiryHeap[K, V]{}
+	return &TTLCache[K, V]{
+		cache:   cache,
+		ttl:     ttl,
+		now:     now,
+		onEvict: onEvict,
+	}
+}
+
+// Get returns the value for key and marks it as the most recently used, unless it has expired.
+func (c *TTLCache[K, V]) Get(key K) (V, bool) {
+	var v V
+	c.mu.Lock()
+	entry, ok := c.cache.items[key]
+	if !ok {
+		c.cache.stats.Misses++
+		c.mu.Unlock()
+		return v, false
+	}
+	if !c.now().Before(entry.expires) {
+		c.cache.stats.Misses++
+		c.cache.stats.Expirations++
+		expired := []evicted[K, V]{{key: key, value: c.cache.remove(key)}}
+		c.mu.Unlock()
+
+		notifyEvicted(c.onEvict, expired)
+		return v, false
+	}
+
+	c.cache.stats.Hits++
+	c.cache.order.MoveToFront(entry.node)
+	v = entry.value
+	c.mu.Unlock()
+	return v, true
+}
+
+// Put stores the value for key with the cache's time to live.
+func (c *TTLCache[K, V]) Put(key K, value V) bool {
+	return c.PutWithTTL(key, value, c.ttl)
+}
+
+// PutWithTTL stores the value for key with its own time to live. If the cache is full, the entry which
+// expired first is dropped to make room, and only if none has expired the least recently used entry is
+// evicted. It returns true if a live entry had to be evicted.
+func (c *TTLCache[K, V]) PutWithTTL(key K, value V, ttl time.Duration) bool {
+	c.mu.Lock()
+	now := c.now()
+	var expired []evicted[K, V]
+	if _, ok := c.cache.items[key]; !ok && c.cache.capacity > 0 && len(c.cache.items) >= c.cache.capacity {
+		if e, ok := c.removeFirstExpired(now); ok {
+			expired = append(expired, e)
+		}
+	}
+	entries := c.cache.put(key, value, now.Add(ttl))
+	c.mu.Unlock()
+
+	notifyEvicted(c.onEvict, expired)
+	notifyEvicted(c.onEvict, entries)
+	return len(entries) > 0
+}
+
+// removeFirstExpired drops the entry which expires first if it has expired. It has to be called with
+// the lock held.
+func (c *TTLCache[K, V]) removeFirstExpired(now time.Time) (evicted[K, V], bool) {
+	h := *c.cache.expiry
+	if len(h) == 0 || now.Before(h[0].expires) {
+		return evicted[K, V]{}, false
+	}
+	key := h[0].node.Data
+	c.cache.stats.Expirations++
+	return evicted[K, V]{key: key, value: c.cache.remove(key)}, true
+}
+
+// removeExpired drops every expired entry and returns them in the order they expired. It has to be
+// called with the lock held.
+func (c *TTLCache[K, V]) removeExpired(now time.Time) []evicted[K, V] {
+	var expired []evicted[K, V]
+	for {
+		e, ok := c.removeFirstExpired(now)
+		if !ok {
+			return expired
+		}
+		expired = append(expired, e)
+	}
+}
+
+// Remove deletes key from the cache without calling the eviction callback. It returns false if the
+// key wasn't there.
+func (c *TTLCache[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.cache.items[key]; !ok {
+		return false
+	}
+	c.cache.remove(key)
+	return true
+}
+
+// Purge drops every expired entry and returns how many there were.
+func (c *TTLCache[K, V]) Purge() int {
+	c.mu.Lock()
+	expired := c.removeExpired(c.now())
+	c.mu.Unlock()
+
+	notifyEvicted(c.onEvict, expired)
+	return len(expired)
+}
+
+// Len returns the number of entries in the cache, including the expired ones which haven't been
+// dropped yet.
+func (c *TTLCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.cache.items)
+}
+
+// Stats returns the statistics of the cache so far.
+func (c *TTLCache[K, V]) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.stats
+}
@@ -0,0 +1,213 @@
+package chapter14
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache(t *testing.T) {
+	var evictedKeys []string
+	c := NewLRUCache(3, func(key string, _ int) {
+		evictedKeys = append(evictedKeys, key)
+	})
+	assert.False(t, c.Put("a", 1))
+	assert.False(t, c.Put("b", 2))
+	assert.False(t, c.Put("c", 3))
+
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, []string{"a", "c", "b"}, c.Keys())
+
+	// b is the least recently used now.
+	assert.True(t, c.Put("d", 4))
+	assert.Equal(t, []string{"b"}, evictedKeys)
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+
+	// Replacing a value marks it as used, but doesn't evict anything.
+	assert.False(t, c.Put("c", 30))
+	assert.Equal(t, []string{"c", "d", "a"}, c.Keys())
+	v, ok = c.Peek("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, []string{"c", "d", "a"}, c.Keys())
+
+	assert.True(t, c.Remove("d"))
+	assert.False(t, c.Remove("d"))
+	assert.Equal(t, 2, c.Len())
+	assert.Equal(t, []string{"b"}, evictedKeys)
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1, Evictions: 1}, c.Stats())
+}
+
+func TestLRUCacheUnbounded(t *testing.T) {
+	c := NewLRUCache[int, int](0, nil)
+	for i := 0; i < 100; i++ {
+		assert.False(t, c.Put(i, i))
+	}
+	assert.Equal(t, 100, c.Len())
+	assert.Equal(t, []int{}, NewLRUCache[int, int](1, nil).Keys())
+}
+
+func TestLRUCacheEvictCallbackReentrant(t *testing.T) {
+	// The callback runs after the cache is unlocked, so it can use the cache.
+	var c *LRUCache[int, int]
+	var lengths []int
+	c = NewLRUCache(1, func(int, int) {
+		lengths = append(lengths, c.Len())
+	})
+	c.Put(1, 1)
+	c.Put(2, 2)
+	assert.Equal(t, []int{1}, lengths)
+}
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func TestTTLCache(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var evictedKeys []string
+	c := NewTTLCache(2, time.Minute, func(key string, _ int) {
+		evictedKeys = append(evictedKeys, key)
+	}, clock.Now)
+
+	c.Put("a", 1)
+	c.PutWithTTL("b", 2, time.Hour)
+	clock.Advance(30 * time.Second)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	clock.Advance(30 * time.Second)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"a"}, evictedKeys)
+	_, ok = c.Get("b")
+	assert.True(t, ok)
+
+	// Putting again resets the time to live.
+	c.Put("c", 3)
+	clock.Advance(50 * time.Second)
+	c.Put("c", 4)
+	clock.Advance(50 * time.Second)
+	v, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+
+	// The cache is full, so b has to go even though it hasn't expired.
+	assert.True(t, c.Put("d", 5))
+	assert.Equal(t, []string{"a", "b"}, evictedKeys)
+	assert.Equal(t, CacheStats{Hits: 3, Misses: 1, Evictions: 1, Expirations: 1}, c.Stats())
+}
+
+func TestTTLCacheEvictsExpiredFirst(t *testing.T) {
+	clock := &fakeClock{}
+	var evictedKeys []string
+	c := NewTTLCache(2, time.Minute, func(key string, _ int) {
+		evictedKeys = append(evictedKeys, key)
+	}, clock.Now)
+
+	c.PutWithTTL("short", 1, time.Second)
+	c.Put("long", 2)
+	// short is the most recently used, but it has expired, so it has to go instead of long.
+	_, _ = c.Get("long")
+	c.PutWithTTL("short", 1, time.Second)
+	clock.Advance(2 * time.Second)
+	assert.False(t, c.Put("new", 3))
+	assert.Equal(t, []string{"short"}, evictedKeys)
+
+	_, ok := c.Get("long")
+	assert.True(t, ok)
+	assert.Equal(t, CacheStats{Hits: 2, Expirations: 1}, c.Stats())
+}
+
+func TestTTLCachePurge(t *testing.T) {
+	clock := &fakeClock{}
+	c := NewTTLCache[int, int](0, time.Second, nil, clock.Now)
+	for i := 0; i < 10; i++ {
+		c.PutWithTTL(i, i, time.Duration(i)*time.Second)
+	}
+	clock.Advance(5 * time.Second)
+	assert.Equal(t, 6, c.Purge())
+	assert.Equal(t, 4, c.Len())
+	assert.Equal(t, 0, c.Purge())
+	assert.True(t, c.Remove(9))
+	assert.Equal(t, uint64(6), c.Stats().Expirations)
+
+	assert.NotNil(t, NewTTLCache[int, int](1, time.Second, nil, nil).now)
+}
+
+func TestTTLCacheExpiryOrder(t *testing.T) {
+	clock := &fakeClock{}
+	var expired []int
+	c := NewTTLCache(0, time.Second, func(key int, _ int) {
+		expired = append(expired, key)
+	}, clock.Now)
+	for i := 0; i < 20; i++ {
+		c.PutWithTTL(i, i, time.Duration(20-i)*time.Second)
+	}
+	// Putting a key again moves it in the expiry order, and removed keys leave it.
+	c.PutWithTTL(0, 0, time.Second/2)
+	c.PutWithTTL(19, 19, time.Hour)
+	for i := 5; i < 10; i++ {
+		assert.True(t, c.Remove(i))
+	}
+
+	clock.Advance(time.Minute)
+	assert.Equal(t, 14, c.Purge())
+	assert.Equal(t, []int{0, 18, 17, 16, 15, 14, 13, 12, 11, 10, 4, 3, 2, 1}, expired)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestCachesConcurrent(t *testing.T) {
+	caches := map[string]interface {
+		Get(key int) (string, bool)
+		Put(key int, value string) bool
+		Len() int
+		Stats() CacheStats
+	}{
+		"LRU": NewLRUCache[int, string](50, nil),
+		"LFU": NewLFUCache[int, string](50, nil),
+		"TTL": NewTTLCache[int, string](50, time.Hour, nil, nil),
+	}
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			for g := 0; g < 8; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < 1000; i++ {
+						key := (g*31 + i) % 100
+						if _, ok := c.Get(key); !ok {
+							c.Put(key, fmt.Sprint(key))
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+
+			assert.Equal(t, 50, c.Len())
+			stats := c.Stats()
+			assert.Equal(t, uint64(8000), stats.Hits+stats.Misses)
+		})
+	}
+}
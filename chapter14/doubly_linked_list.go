@@ -1,78 +1,267 @@
 package chapter14
 
+import "github.com/Skarlso/data-structures-and-algoritms/chapter09"
+
 type DoubleNode[T comparable] struct {
 	NextNode     *DoubleNode[T]
 	PreviousNode *DoubleNode[T]
 	Data         T
+	// list is the list the node is in, so removing or moving a node can check it belongs to the list.
+	list *DoublyLinkedList[T]
 }
 
 type DoublyLinkedList[T comparable] struct {
 	FirstNode *DoubleNode[T]
 	LastNode  *DoubleNode[T]
+	length    int
 }
 
+// NewDoublyLinkedList creates a list from already linked nodes. Either both nodes are nil, or following
+// NextNode from firstNode has to end at lastNode.
 func NewDoublyLinkedList[T comparable](firstNode, lastNode *DoubleNode[T]) *DoublyLinkedList[T] {
-	return &DoublyLinkedList[T]{
+	t := &DoublyLinkedList[T]{
 		FirstNode: firstNode,
 		LastNode:  lastNode,
 	}
+	for n := firstNode; n != nil; n = n.NextNode {
+		n.list = t
+		t.length++
+	}
+	return t
 }
 
-func (t *DoublyLinkedList[T]) InsertAtEnd(val T) {
-	newNode := &DoubleNode[T]{
-		Data: val,
+// Len returns the number of nodes in the list.
+func (t *DoublyLinkedList[T]) Len() int {
+	return t.length
+}
+
+// insertAfter links node in after previous. A nil previous inserts it at the front.
+func (t *DoublyLinkedList[T]) insertAfter(node, previous *DoubleNode[T]) *DoubleNode[T] {
+	node.list = t
+	node.PreviousNode = previous
+	if previous == nil {
+		node.NextNode = t.FirstNode
+		t.FirstNode = node
+	} else {
+		node.NextNode = previous.NextNode
+		previous.NextNode = node
 	}
-	if t.FirstNode == nil {
-		t.FirstNode = newNode
-		t.LastNode = newNode
+
+	if node.NextNode == nil {
+		t.LastNode = node
 	} else {
-		// Update the last node to be the new node and update the previously last node's link to point to this
-		// newly inserted node.
-		newNode.PreviousNode = t.LastNode
-		t.LastNode.NextNode = newNode
-		t.LastNode = newNode
+		node.NextNode.PreviousNode = node
+	}
+	t.length++
+	return node
+}
+
+// unlink takes node out of the list and clears its links.
+func (t *DoublyLinkedList[T]) unlink(node *DoubleNode[T]) {
+	if node.PreviousNode == nil {
+		t.FirstNode = node.NextNode
+	} else {
+		node.PreviousNode.NextNode = node.NextNode
+	}
+	if node.NextNode == nil {
+		t.LastNode = node.PreviousNode
+	} else {
+		node.NextNode.PreviousNode = node.PreviousNode
+	}
+
+	node.NextNode = nil
+	node.PreviousNode = nil
+	node.list = nil
+	t.length--
+}
+
+// PushFront adds a value to the start of the list and returns its node.
+func (t *DoublyLinkedList[T]) PushFront(val T) *DoubleNode[T] {
+	return t.insertAfter(&DoubleNode[T]{Data: val}, nil)
+}
+
+// PushBack adds a value to the end of the list and returns its node.
+func (t *DoublyLinkedList[T]) PushBack(val T) *DoubleNode[T] {
+	return t.insertAfter(&DoubleNode[T]{Data: val}, t.LastNode)
+}
+
+func (t *DoublyLinkedList[T]) InsertAtEnd(val T) {
+	t.PushBack(val)
+}
+
+// InsertBefore adds a value right before mark and returns its node. It returns nil if mark isn't in
+// the list.
+func (t *DoublyLinkedList[T]) InsertBefore(val T, mark *DoubleNode[T]) *DoubleNode[T] {
+	if mark == nil || mark.list != t {
+		return nil
+	}
+	return t.insertAfter(&DoubleNode[T]{Data: val}, mark.PreviousNode)
+}
+
+// InsertAfter adds a value right after mark and returns its node. It returns nil if mark isn't in
+// the list.
+func (t *DoublyLinkedList[T]) InsertAfter(val T, mark *DoubleNode[T]) *DoubleNode[T] {
+	if mark == nil || mark.list != t {
+		return nil
+	}
+	return t.insertAfter(&DoubleNode[T]{Data: val}, mark)
+}
+
+// Remove takes node out of the list. It returns false if node isn't in the list.
+func (t *DoublyLinkedList[T]) Remove(node *DoubleNode[T]) bool {
+	if node == nil || node.list != t {
+		return false
+	}
+	t.unlink(node)
+	return true
+}
+
+// MoveToFront moves node to the start of the list. It returns false if node isn't in the list.
+func (t *DoublyLinkedList[T]) MoveToFront(node *DoubleNode[T]) bool {
+	if node == nil || node.list != t {
+		return false
+	}
+	if node != t.FirstNode {
+		t.unlink(node)
+		t.insertAfter(node, nil)
 	}
+	return true
 }
 
+// MoveToBack moves node to the end of the list. It returns false if node isn't in the list.
+func (t *DoublyLinkedList[T]) MoveToBack(node *DoubleNode[T]) bool {
+	if node == nil || node.list != t {
+		return false
+	}
+	if node != t.LastNode {
+		t.unlink(node)
+		t.insertAfter(node, t.LastNode)
+	}
+	return true
+}
+
+// DeleteFromFront removes the first node and returns it, or nil if the list is empty.
 func (t *DoublyLinkedList[T]) DeleteFromFront() *DoubleNode[T] {
 	removedNode := t.FirstNode
-	t.FirstNode = t.FirstNode.NextNode
+	if removedNode != nil {
+		t.unlink(removedNode)
+	}
 	return removedNode
 }
 
+// PopFront removes the first value and returns it. It returns false if the list is empty.
+func (t *DoublyLinkedList[T]) PopFront() (T, bool) {
+	if t.FirstNode == nil {
+		var v T
+		return v, false
+	}
+	return t.DeleteFromFront().Data, true
+}
+
+// PopBack removes the last value and returns it. It returns false if the list is empty.
+func (t *DoublyLinkedList[T]) PopBack() (T, bool) {
+	removedNode := t.LastNode
+	if removedNode == nil {
+		var v T
+		return v, false
+	}
+	t.unlink(removedNode)
+	return removedNode.Data, true
+}
+
+func (t *DoublyLinkedList[T]) Traverse() []T {
+	var result []T
+	for it := t.Iterator(); it.Next(); {
+		result = append(result, it.Value())
+	}
+	return result
+}
+
 func (t *DoublyLinkedList[T]) ReverseTraverse() []T {
 	var result []T
-	curr := t.LastNode
-	for curr != nil {
-		result = append(result, curr.Data)
-		curr = curr.PreviousNode
+	for it := t.ReverseIterator(); it.Next(); {
+		result = append(result, it.Value())
 	}
 	return result
 }
 
+// DoubleIterator walks a DoublyLinkedList in one direction:
+//
+//	for it := list.Iterator(); it.Next(); {
+//		fmt.Println(it.Value())
+//	}
+//
+// The node it's on can be removed while iterating, the iterator has already looked at the one after it.
+type DoubleIterator[T comparable] struct {
+	node    *DoubleNode[T]
+	next    *DoubleNode[T]
+	reverse bool
+}
+
+// Iterator returns an iterator from the first node to the last.
+func (t *DoublyLinkedList[T]) Iterator() *DoubleIterator[T] {
+	return &DoubleIterator[T]{next: t.FirstNode}
+}
+
+// ReverseIterator returns an iterator from the last node to the first.
+func (t *DoublyLinkedList[T]) ReverseIterator() *DoubleIterator[T] {
+	return &DoubleIterator[T]{next: t.LastNode, reverse: true}
+}
+
+// Next moves to the next node. It returns false when there are no more nodes.
+func (it *DoubleIterator[T]) Next() bool {
+	it.node = it.next
+	if it.node == nil {
+		return false
+	}
+	if it.reverse {
+		it.next = it.node.PreviousNode
+	} else {
+		it.next = it.node.NextNode
+	}
+	return true
+}
+
+// Node returns the node the iterator is on.
+func (it *DoubleIterator[T]) Node() *DoubleNode[T] {
+	return it.node
+}
+
+// Value returns the data of the node the iterator is on.
+func (it *DoubleIterator[T]) Value() T {
+	return it.node.Data
+}
+
+// Queue is a first in, first out queue. It used to be built on DoublyLinkedList, but a ring buffer
+// doesn't have to allocate a node for every value.
 type Queue[T comparable] struct {
-	queue *DoublyLinkedList[T]
+	queue *chapter09.Deque[T]
 }
 
 func NewQueue[T comparable]() *Queue[T] {
 	return &Queue[T]{
-		queue: NewDoublyLinkedList[T](nil, nil),
+		queue: chapter09.NewDeque[T](),
 	}
 }
 
 func (q *Queue[T]) Enqueue(val T) {
-	q.queue.InsertAtEnd(val)
+	// A growing Deque never rejects a value.
+	_ = q.queue.PushBack(val)
 }
 
+// Dequeue removes the first value and returns it, or the zero value if the queue is empty.
 func (q *Queue[T]) Dequeue() T {
-	removedNode := q.queue.DeleteFromFront()
-	return removedNode.Data
+	v, _ := q.queue.PopFront()
+	return v
 }
 
+// Read returns the first value without removing it, or the zero value if the queue is empty.
 func (q *Queue[T]) Read() T {
-	if q.queue.FirstNode == nil {
-		return q.queue.FirstNode.Data
-	}
-	var t T
-	return t
+	v, _ := q.queue.Front()
+	return v
+}
+
+// Len returns the number of values in the queue.
+func (q *Queue[T]) Len() int {
+	return q.queue.Len()
 }
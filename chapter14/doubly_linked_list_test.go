@@ -22,3 +22,159 @@ func TestReverseTraverse(t *testing.T) {
 	list := n.ReverseTraverse()
 	assert.Equal(t, []int{3, 2, 1}, list)
 }
+
+func checkLinks[T comparable](t *testing.T, list *DoublyLinkedList[T]) {
+	t.Helper()
+	count := 0
+	var previous *DoubleNode[T]
+	for n := list.FirstNode; n != nil; n = n.NextNode {
+		assert.Same(t, previous, n.PreviousNode)
+		previous = n
+		count++
+	}
+	assert.Same(t, previous, list.LastNode)
+	assert.Equal(t, count, list.Len())
+}
+
+func TestDoublyLinkedListPushPop(t *testing.T) {
+	list := NewDoublyLinkedList[int](nil, nil)
+	list.PushBack(2)
+	list.PushFront(1)
+	list.InsertAtEnd(3)
+	checkLinks(t, list)
+	assert.Equal(t, []int{1, 2, 3}, list.Traverse())
+
+	v, ok := list.PopBack()
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	v, ok = list.PopFront()
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	checkLinks(t, list)
+
+	v, ok = list.PopBack()
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	assert.Nil(t, list.FirstNode)
+	assert.Nil(t, list.LastNode)
+	_, ok = list.PopBack()
+	assert.False(t, ok)
+	_, ok = list.PopFront()
+	assert.False(t, ok)
+	assert.Equal(t, 0, list.Len())
+}
+
+func TestDeleteFromFront(t *testing.T) {
+	list := NewDoublyLinkedList[int](nil, nil)
+	assert.Nil(t, list.DeleteFromFront())
+
+	list.PushBack(1)
+	list.PushBack(2)
+	removed := list.DeleteFromFront()
+	assert.Equal(t, 1, removed.Data)
+	assert.Nil(t, removed.NextNode)
+	assert.Nil(t, list.FirstNode.PreviousNode)
+	checkLinks(t, list)
+
+	list.DeleteFromFront()
+	assert.Nil(t, list.FirstNode)
+	assert.Nil(t, list.LastNode)
+	assert.Nil(t, list.ReverseTraverse())
+}
+
+func TestDoublyLinkedListInsertRemove(t *testing.T) {
+	list := NewDoublyLinkedList[string](nil, nil)
+	b := list.PushBack("b")
+	d := list.PushBack("d")
+	a := list.InsertBefore("a", b)
+	list.InsertAfter("c", b)
+	e := list.InsertAfter("e", d)
+	checkLinks(t, list)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, list.Traverse())
+	assert.Same(t, e, list.LastNode)
+
+	assert.True(t, list.Remove(b))
+	assert.True(t, list.Remove(a))
+	assert.True(t, list.Remove(e))
+	checkLinks(t, list)
+	assert.Equal(t, []string{"c", "d"}, list.Traverse())
+
+	// Nodes which aren't in the list are rejected.
+	assert.False(t, list.Remove(b))
+	assert.Nil(t, list.InsertAfter("x", b))
+	other := NewDoublyLinkedList[string](nil, nil)
+	foreign := other.PushBack("x")
+	assert.False(t, list.Remove(foreign))
+	assert.False(t, list.MoveToFront(foreign))
+	assert.Nil(t, list.InsertBefore("y", foreign))
+	assert.Equal(t, 2, list.Len())
+	assert.Equal(t, 1, other.Len())
+}
+
+func TestDoublyLinkedListMove(t *testing.T) {
+	list := NewDoublyLinkedList[int](nil, nil)
+	nodes := make([]*DoubleNode[int], 4)
+	for i := range nodes {
+		nodes[i] = list.PushBack(i)
+	}
+
+	assert.True(t, list.MoveToFront(nodes[2]))
+	assert.Equal(t, []int{2, 0, 1, 3}, list.Traverse())
+	assert.True(t, list.MoveToFront(nodes[3]))
+	assert.True(t, list.MoveToFront(nodes[3]))
+	assert.Equal(t, []int{3, 2, 0, 1}, list.Traverse())
+	assert.True(t, list.MoveToBack(nodes[3]))
+	assert.Equal(t, []int{2, 0, 1, 3}, list.Traverse())
+	checkLinks(t, list)
+}
+
+func TestDoublyLinkedListIterators(t *testing.T) {
+	list := NewDoublyLinkedList[int](nil, nil)
+	for i := 0; i < 6; i++ {
+		list.PushBack(i)
+	}
+
+	// Removing while iterating is fine.
+	for it := list.Iterator(); it.Next(); {
+		if it.Value()%2 == 1 {
+			list.Remove(it.Node())
+		}
+	}
+	assert.Equal(t, []int{0, 2, 4}, list.Traverse())
+
+	var reversed []int
+	for it := list.ReverseIterator(); it.Next(); {
+		reversed = append(reversed, it.Value())
+	}
+	assert.Equal(t, []int{4, 2, 0}, reversed)
+
+	empty := NewDoublyLinkedList[int](nil, nil)
+	assert.False(t, empty.Iterator().Next())
+	assert.False(t, empty.ReverseIterator().Next())
+}
+
+func TestNewDoublyLinkedListLen(t *testing.T) {
+	first := &DoubleNode[int]{Data: 1}
+	last := &DoubleNode[int]{Data: 2, PreviousNode: first}
+	first.NextNode = last
+	list := NewDoublyLinkedList(first, last)
+	assert.Equal(t, 2, list.Len())
+	assert.True(t, list.Remove(first))
+	checkLinks(t, list)
+}
+
+func TestQueue(t *testing.T) {
+	q := NewQueue[int]()
+	assert.Equal(t, 0, q.Read())
+	assert.Equal(t, 0, q.Dequeue())
+
+	for i := 1; i <= 20; i++ {
+		q.Enqueue(i)
+	}
+	assert.Equal(t, 1, q.Read())
+	assert.Equal(t, 20, q.Len())
+	for i := 1; i <= 20; i++ {
+		assert.Equal(t, i, q.Dequeue())
+	}
+	assert.Equal(t, 0, q.Len())
+}
@@ -0,0 +1,159 @@
+package chapter14
+
+import "sync"
+
+type lfuEntry[K comparable, V any] struct {
+	value     V
+	frequency int
+	node      *DoubleNode[K]
+}
+
+// LFUCache keeps up to capacity entries and evicts the least frequently used one to make room for a new
+// one. Among entries used equally often, the least recently used one goes first. Keys are kept in a
+// list per frequency, so Get and Put are O(1). Remove is O(1) too, unless it takes the last key used
+// the fewest times, when finding the new lowest frequency is O(f) for f distinct frequencies. It's safe
+// for concurrent use.
+type LFUCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[K]*lfuEntry[K, V]
+	// frequencies holds the keys used exactly that many times, from the most recently used.
+	frequencies  map[int]*DoublyLinkedList[K]
+	minFrequency int
+	stats        CacheStats
+	onEvict      func(key K, value V)
+}
+
+// NewLFUCache creates a cache of the given capacity, where just like for NewLRUCache a capacity of 0 or
+// less means the cache is never full. onEvict is called for every entry evicted to make room, and may
+// be nil.
+func NewLFUCache[K comparable, V any](capacity int, onEvict func(key K, value V)) *LFUCache[K, V] {
+	return &LFUCache[K, V]{
+		capacity:    capacity,
+		items:       make(map[K]*lfuEntry[K, V]),
+		frequencies: make(map[int]*DoublyLinkedList[K]),
+		onEvict:     onEvict,
+	}
+}
+
+func (c *LFUCache[K, V]) list(frequency int) *DoublyLinkedList[K] {
+	list, ok := c.frequencies[frequency]
+	if !ok {
+		list = NewDoublyLinkedList[K](nil, nil)
+		c.frequencies[frequency] = list
+	}
+	return list
+}
+
+// unlink takes the key of entry out of its frequency list, dropping the list once it's empty.
+func (c *LFUCache[K, V]) unlink(entry *lfuEntry[K, V]) {
+	list := c.frequencies[entry.frequency]
+	list.Remove(entry.node)
+	if list.Len() == 0 {
+		delete(c.frequencies, entry.frequency)
+	}
+}
+
+func (c *LFUCache[K, V]) touch(key K, entry *lfuEntry[K, V]) {
+	c.unlink(entry)
+	if entry.frequency == c.minFrequency && c.frequencies[entry.frequency] == nil {
+		c.minFrequency++
+	}
+	entry.frequency++
+	entry.node = c.list(entry.frequency).PushFront(key)
+}
+
+// Get returns the value for key and counts it as used.
+func (c *LFUCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		var v V
+		return v, false
+	}
+	c.stats.Hits++
+	c.touch(key, entry)
+	return entry.value, true
+}
+
+// Put stores the value for key. Replacing a value counts as a use of the key. It returns true if another
+// entry had to be evicted for it.
+func (c *LFUCache[K, V]) Put(key K, value V) bool {
+	c.mu.Lock()
+	if entry, ok := c.items[key]; ok {
+		entry.value = value
+		c.touch(key, entry)
+		c.mu.Unlock()
+		return false
+	}
+
+	var entries []evicted[K, V]
+	if c.capacity > 0 && len(c.items) >= c.capacity {
+		victim := c.frequencies[c.minFrequency].LastNode.Data
+		entry := c.items[victim]
+		c.unlink(entry)
+		delete(c.items, victim)
+		c.stats.Evictions++
+		entries = append(entries, evicted[K, V]{key: victim, value: entry.value})
+	}
+
+	c.items[key] = &lfuEntry[K, V]{value: value, frequency: 1, node: c.list(1).PushFront(key)}
+	c.minFrequency = 1
+	c.mu.Unlock()
+
+	notifyEvicted(c.onEvict, entries)
+	return len(entries) > 0
+}
+
+// Remove deletes key from the cache without calling the eviction callback. It returns false if the
+// key wasn't there.
+func (c *LFUCache[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.unlink(entry)
+	delete(c.items, key)
+	if c.frequencies[c.minFrequency] == nil {
+		// The lowest frequency is gone. Finding the next one means looking through all of them, but
+		// that only happens on removal, never on Get or Put.
+		c.minFrequency = 0
+		for frequency := range c.frequencies {
+			if c.minFrequency == 0 || frequency < c.minFrequency {
+				c.minFrequency = frequency
+			}
+		}
+	}
+	return true
+}
+
+// Frequency returns how often key has been used, or 0 if it isn't in the cache.
+func (c *LFUCache[K, V]) Frequency(key K) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.items[key]; ok {
+		return entry.frequency
+	}
+	return 0
+}
+
+// Len returns the number of entries in the cache.
+func (c *LFUCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Stats returns the statistics of the cache so far.
+func (c *LFUCache[K, V]) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
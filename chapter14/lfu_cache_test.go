@@ -0,0 +1,70 @@
+package chapter14
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLFUCache(t *testing.T) {
+	var evictedKeys []string
+	c := NewLFUCache(3, func(key string, _ int) {
+		evictedKeys = append(evictedKeys, key)
+	})
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	assert.Equal(t, 3, c.Frequency("a"))
+	assert.Equal(t, 2, c.Frequency("b"))
+	assert.Equal(t, 1, c.Frequency("c"))
+
+	assert.True(t, c.Put("d", 4))
+	assert.Equal(t, []string{"c"}, evictedKeys)
+
+	// d and b are now used just as often as each other after one more Get, and b was used longer ago.
+	c.Get("d")
+	assert.True(t, c.Put("e", 5))
+	assert.Equal(t, []string{"c", "b"}, evictedKeys)
+
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Frequency("b"))
+
+	// Replacing a value counts as a use.
+	assert.False(t, c.Put("e", 50))
+	assert.Equal(t, 2, c.Frequency("e"))
+	assert.Equal(t, CacheStats{Hits: 5, Misses: 1, Evictions: 2}, c.Stats())
+}
+
+func TestLFUCacheRemove(t *testing.T) {
+	c := NewLFUCache[int, int](2, nil)
+	c.Put(1, 1)
+	c.Put(2, 2)
+	c.Get(2)
+	c.Get(2)
+	assert.True(t, c.Remove(1))
+	assert.False(t, c.Remove(1))
+	assert.Equal(t, 1, c.Len())
+
+	// Only 2 is left with a frequency of 3, so it has to be found as the least frequent key.
+	c.Put(3, 3)
+	c.Get(3)
+	assert.True(t, c.Put(4, 4))
+	_, ok := c.Get(3)
+	assert.False(t, ok)
+	_, ok = c.Get(2)
+	assert.True(t, ok)
+
+	// Just like the LRU cache, a capacity of 0 means there is no limit.
+	unbounded := NewLFUCache[int, int](0, nil)
+	for i := 0; i < 100; i++ {
+		assert.False(t, unbounded.Put(i, i))
+	}
+	assert.Equal(t, 100, unbounded.Len())
+}
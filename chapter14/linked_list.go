@@ -18,32 +18,18 @@ func NewLinkedList[T comparable](firstNode *Node[T]) *Tree[T] {
 
 // NewListFromSlice creates a new linked list using a list of any items.
 func NewLinkedListFromSlice[T comparable](list []T) *Tree[T] {
-	if len(list) == 0 {
-		return &Tree[T]{
-			FirstNode: &Node[T]{},
-		}
-	}
-
-	start := &Node[T]{
-		Data: list[0],
+	t := &Tree[T]{}
+	// Build the list backwards, so every new node can simply point at the one after it.
+	for i := len(list) - 1; i >= 0; i-- {
+		t.FirstNode = &Node[T]{Next: t.FirstNode, Data: list[i]}
 	}
-	last := &Node[T]{}
-	start.Next = last
-	for i := 1; i < len(list)-1; i++ {
-		last.Data = list[i]
-		last.Next = &Node[T]{}
-		last = last.Next
-	}
-
-	last.Data = list[len(list)-1]
-	return &Tree[T]{FirstNode: start}
+	return t
 }
 
 // Traverse creates a slice from the linked list.
 func (t *Tree[T]) Traverse() []T {
-	n := t.FirstNode
-	result := append([]T{}, n.Data)
-	node := n.Next
+	result := []T{}
+	node := t.FirstNode
 	for node != nil {
 		result = append(result, node.Data)
 		node = node.Next
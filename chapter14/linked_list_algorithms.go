@@ -0,0 +1,150 @@
+package chapter14
+
+// DetectCycle finds out whether following Next from head ever comes back to a node it has seen,
+// using Floyd's tortoise and hare. It returns the first node of the cycle and the number of nodes in
+// it, or nil and 0 if the list ends.
+func DetectCycle[T comparable](head *Node[T]) (*Node[T], int) {
+	slow, fast := head, head
+	for fast != nil && fast.Next != nil {
+		slow = slow.Next
+		fast = fast.Next.Next
+		if slow != fast {
+			continue
+		}
+
+		// They met inside the cycle. The start of the cycle is as far from head as it is from the
+		// meeting point going forward, so walking from both at the same pace meets right at the start.
+		start := head
+		for start != slow {
+			start = start.Next
+			slow = slow.Next
+		}
+		length := 1
+		for n := start.Next; n != start; n = n.Next {
+			length++
+		}
+		return start, length
+	}
+	return nil, 0
+}
+
+// Middle returns the middle node of the list. For an even number of nodes it's the second of the two
+// in the middle.
+func (t *Tree[T]) Middle() *Node[T] {
+	slow, fast := t.FirstNode, t.FirstNode
+	for fast != nil && fast.Next != nil {
+		slow = slow.Next
+		fast = fast.Next.Next
+	}
+	return slow
+}
+
+// KthFromEnd returns the k-th node counting back from the end, where 1 is the last node. It returns nil
+// if the list is shorter than k.
+func (t *Tree[T]) KthFromEnd(k int) *Node[T] {
+	if k < 1 {
+		return nil
+	}
+
+	// Run ahead k nodes, then walk both until the leading one falls off the end.
+	lead := t.FirstNode
+	for i := 0; i < k; i++ {
+		if lead == nil {
+			return nil
+		}
+		lead = lead.Next
+	}
+	result := t.FirstNode
+	for lead != nil {
+		lead = lead.Next
+		result = result.Next
+	}
+	return result
+}
+
+// ReverseInGroups reverses every group of k nodes in place. If the last group has less than k nodes, it
+// is left as it is.
+func (t *Tree[T]) ReverseInGroups(k int) {
+	if k < 2 {
+		return
+	}
+
+	// link is where the next reversed group gets attached.
+	link := &t.FirstNode
+	for {
+		groupEnd := *link
+		for i := 1; i < k && groupEnd != nil; i++ {
+			groupEnd = groupEnd.Next
+		}
+		if groupEnd == nil {
+			return
+		}
+
+		first, rest := *link, groupEnd.Next
+		previous := rest
+		for n := first; n != rest; {
+			next := n.Next
+			n.Next = previous
+			previous = n
+			n = next
+		}
+		*link = groupEnd
+		link = &first.Next
+	}
+}
+
+// Sort sorts the list in place with merge sort. Equal values keep their order.
+func (t *Tree[T]) Sort(less func(a, b T) bool) {
+	t.FirstNode = mergeSort(t.FirstNode, less)
+}
+
+func mergeSort[T comparable](head *Node[T], less func(a, b T) bool) *Node[T] {
+	if head == nil || head.Next == nil {
+		return head
+	}
+
+	// Split after the first middle node, so a list of two is split into two lists of one.
+	slow, fast := head, head.Next
+	for fast != nil && fast.Next != nil {
+		slow = slow.Next
+		fast = fast.Next.Next
+	}
+	second := slow.Next
+	slow.Next = nil
+
+	return MergeSorted(mergeSort(head, less), mergeSort(second, less), less)
+}
+
+// MergeSorted merges two sorted lists into one by relinking their nodes. On equal values the node from
+// a comes first.
+func MergeSorted[T comparable](a, b *Node[T], less func(a, b T) bool) *Node[T] {
+	var head Node[T]
+	tail := &head
+	for a != nil && b != nil {
+		if less(b.Data, a.Data) {
+			tail.Next, b = b, b.Next
+		} else {
+			tail.Next, a = a, a.Next
+		}
+		tail = tail.Next
+	}
+	if a != nil {
+		tail.Next = a
+	} else {
+		tail.Next = b
+	}
+	return head.Next
+}
+
+// MergeKSorted merges any number of sorted lists into one. Lists are merged in pairs, so every node is
+// only moved O(log k) times. On equal values nodes from earlier lists come first.
+func MergeKSorted[T comparable](lists []*Node[T], less func(a, b T) bool) *Node[T] {
+	switch len(lists) {
+	case 0:
+		return nil
+	case 1:
+		return lists[0]
+	}
+	mid := len(lists) / 2
+	return MergeSorted(MergeKSorted(lists[:mid], less), MergeKSorted(lists[mid:], less), less)
+}
@@ -0,0 +1,153 @@
+package chapter14
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lessInt(a, b int) bool {
+	return a < b
+}
+
+func TestDetectCycle(t *testing.T) {
+	start, length := DetectCycle[int](nil)
+	assert.Nil(t, start)
+	assert.Equal(t, 0, length)
+
+	single := &Node[int]{Data: 1}
+	start, length = DetectCycle(single)
+	assert.Nil(t, start)
+	assert.Equal(t, 0, length)
+
+	single.Next = single
+	start, length = DetectCycle(single)
+	assert.Same(t, single, start)
+	assert.Equal(t, 1, length)
+
+	for tail := 0; tail < 5; tail++ {
+		for cycle := 1; cycle < 6; cycle++ {
+			list := NewLinkedListFromSlice(make([]int, tail+cycle))
+			cycleStart := list.Read(tail)
+			list.LastNode().Next = cycleStart
+
+			start, length := DetectCycle(list.FirstNode)
+			assert.Same(t, cycleStart, start, "tail %d cycle %d", tail, cycle)
+			assert.Equal(t, cycle, length)
+		}
+	}
+
+	start, length = DetectCycle(NewLinkedListFromSlice([]int{1, 2, 3}).FirstNode)
+	assert.Nil(t, start)
+	assert.Equal(t, 0, length)
+}
+
+func TestMiddle(t *testing.T) {
+	assert.Nil(t, NewLinkedListFromSlice([]int{}).Middle())
+	assert.Equal(t, 1, NewLinkedListFromSlice([]int{1}).Middle().Data)
+	assert.Equal(t, 2, NewLinkedListFromSlice([]int{1, 2}).Middle().Data)
+	assert.Equal(t, 3, NewLinkedListFromSlice([]int{1, 2, 3, 4, 5}).Middle().Data)
+	assert.Equal(t, 4, NewLinkedListFromSlice([]int{1, 2, 3, 4, 5, 6}).Middle().Data)
+}
+
+func TestKthFromEnd(t *testing.T) {
+	assert.Nil(t, NewLinkedListFromSlice([]int{}).KthFromEnd(1))
+	single := NewLinkedListFromSlice([]int{1})
+	assert.Equal(t, 1, single.KthFromEnd(1).Data)
+	assert.Nil(t, single.KthFromEnd(2))
+
+	list := NewLinkedListFromSlice([]int{1, 2, 3, 4, 5})
+	assert.Equal(t, 5, list.KthFromEnd(1).Data)
+	assert.Equal(t, 4, list.KthFromEnd(2).Data)
+	assert.Equal(t, 1, list.KthFromEnd(5).Data)
+	assert.Nil(t, list.KthFromEnd(6))
+	assert.Nil(t, list.KthFromEnd(0))
+}
+
+func TestReverseInGroups(t *testing.T) {
+	tests := []struct {
+		values []int
+		k      int
+		want   []int
+	}{
+		{values: []int{}, k: 2, want: []int{}},
+		{values: []int{1}, k: 2, want: []int{1}},
+		{values: []int{1}, k: 1, want: []int{1}},
+		{values: []int{1, 2, 3, 4, 5}, k: 1, want: []int{1, 2, 3, 4, 5}},
+		{values: []int{1, 2, 3, 4, 5}, k: 2, want: []int{2, 1, 4, 3, 5}},
+		{values: []int{1, 2, 3, 4, 5, 6}, k: 3, want: []int{3, 2, 1, 6, 5, 4}},
+		{values: []int{1, 2, 3, 4, 5}, k: 5, want: []int{5, 4, 3, 2, 1}},
+		{values: []int{1, 2, 3}, k: 4, want: []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		list := NewLinkedListFromSlice(tt.values)
+		list.ReverseInGroups(tt.k)
+		assert.Equal(t, tt.want, list.Traverse(), "%v in groups of %d", tt.values, tt.k)
+	}
+}
+
+func TestSortList(t *testing.T) {
+	for _, values := range [][]int{{}, {1}, {2, 1}, {3, 1, 2, 1, 3}} {
+		list := NewLinkedListFromSlice(values)
+		list.Sort(lessInt)
+		want := append([]int{}, values...)
+		sort.Ints(want)
+		assert.Equal(t, want, list.Traverse())
+	}
+
+	r := rand.New(rand.NewSource(1))
+	values := make([]int, 1000)
+	for i := range values {
+		values[i] = r.Intn(100)
+	}
+	list := NewLinkedListFromSlice(values)
+	list.Sort(lessInt)
+	sort.Ints(values)
+	assert.Equal(t, values, list.Traverse())
+}
+
+func TestSortListStable(t *testing.T) {
+	type item struct {
+		key, order int
+	}
+	var values []item
+	for i := 0; i < 50; i++ {
+		values = append(values, item{key: (i * 7) % 5, order: i})
+	}
+	list := NewLinkedListFromSlice(values)
+	list.Sort(func(a, b item) bool { return a.key < b.key })
+
+	want := append([]item{}, values...)
+	sort.SliceStable(want, func(i, j int) bool { return want[i].key < want[j].key })
+	assert.Equal(t, want, list.Traverse())
+}
+
+func TestMergeSorted(t *testing.T) {
+	merged := MergeSorted(
+		NewLinkedListFromSlice([]int{1, 3, 5, 7}).FirstNode,
+		NewLinkedListFromSlice([]int{2, 3, 8}).FirstNode,
+		lessInt,
+	)
+	assert.Equal(t, []int{1, 2, 3, 3, 5, 7, 8}, NewLinkedList(merged).Traverse())
+
+	assert.Nil(t, MergeSorted[int](nil, nil, lessInt))
+	single := &Node[int]{Data: 1}
+	assert.Same(t, single, MergeSorted(nil, single, lessInt))
+	assert.Same(t, single, MergeSorted(single, nil, lessInt))
+}
+
+func TestMergeKSorted(t *testing.T) {
+	assert.Nil(t, MergeKSorted[int](nil, lessInt))
+
+	lists := []*Node[int]{
+		NewLinkedListFromSlice([]int{1, 4, 7}).FirstNode,
+		nil,
+		NewLinkedListFromSlice([]int{2, 5, 8}).FirstNode,
+		NewLinkedListFromSlice([]int{0}).FirstNode,
+		NewLinkedListFromSlice([]int{3, 6, 9, 10}).FirstNode,
+	}
+	merged := MergeKSorted(lists, lessInt)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, NewLinkedList(merged).Traverse())
+}
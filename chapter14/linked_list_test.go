@@ -133,3 +133,15 @@ func TestReverseList(t *testing.T) {
 	last := tree.LastNode()
 	assert.Equal(t, 1, last.Data)
 }
+
+func TestLinkedListEmpty(t *testing.T) {
+	tree := NewLinkedListFromSlice([]int{})
+	assert.Nil(t, tree.FirstNode)
+	assert.Equal(t, []int{}, tree.Traverse())
+	assert.Equal(t, -1, tree.Search(0))
+}
+
+func TestLinkedListFromSliceSingle(t *testing.T) {
+	tree := NewLinkedListFromSlice([]int{1})
+	assert.Equal(t, &Node[int]{Data: 1}, tree.FirstNode)
+}
@@ -0,0 +1,185 @@
+package chapter14
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrIndexOutOfRange is returned when accessing a position which isn't in the list.
+var ErrIndexOutOfRange = errors.New("index out of range")
+
+type listNode[T any] struct {
+	next *listNode[T]
+	data T
+}
+
+// List is a singly linked list which keeps track of its last node and its length, so appending and
+// Len don't have to walk the list. The nodes are hidden, so the list can't get out of sync with them.
+type List[T any] struct {
+	head   *listNode[T]
+	tail   *listNode[T]
+	length int
+}
+
+// NewList creates an empty list.
+func NewList[T any]() *List[T] {
+	return &List[T]{}
+}
+
+// ListFromSlice creates a list holding the values in the same order.
+func ListFromSlice[T any](values []T) *List[T] {
+	l := NewList[T]()
+	for _, v := range values {
+		l.PushBack(v)
+	}
+	return l
+}
+
+// Len returns the number of values in the list.
+func (l *List[T]) Len() int {
+	return l.length
+}
+
+// PushFront adds a value to the start of the list.
+func (l *List[T]) PushFront(val T) {
+	l.head = &listNode[T]{next: l.head, data: val}
+	if l.tail == nil {
+		l.tail = l.head
+	}
+	l.length++
+}
+
+// PushBack adds a value to the end of the list.
+func (l *List[T]) PushBack(val T) {
+	node := &listNode[T]{data: val}
+	if l.tail == nil {
+		l.head = node
+	} else {
+		l.tail.next = node
+	}
+	l.tail = node
+	l.length++
+}
+
+func (l *List[T]) checkIndex(index, length int) error {
+	if index < 0 || index >= length {
+		return fmt.Errorf("%w: %d is not in [0, %d)", ErrIndexOutOfRange, index, length)
+	}
+	return nil
+}
+
+// node returns the node at index, which has to be a valid index.
+func (l *List[T]) node(index int) *listNode[T] {
+	// The last node is the most likely one after the first, and it's free to get to.
+	if index == l.length-1 {
+		return l.tail
+	}
+	node := l.head
+	for i := 0; i < index; i++ {
+		node = node.next
+	}
+	return node
+}
+
+// Get returns the value at index.
+func (l *List[T]) Get(index int) (T, error) {
+	if err := l.checkIndex(index, l.length); err != nil {
+		var t T
+		return t, err
+	}
+	return l.node(index).data, nil
+}
+
+// Set replaces the value at index.
+func (l *List[T]) Set(index int, val T) error {
+	if err := l.checkIndex(index, l.length); err != nil {
+		return err
+	}
+	l.node(index).data = val
+	return nil
+}
+
+// InsertAt inserts a value so that it ends up at index. Inserting at Len appends the value.
+func (l *List[T]) InsertAt(index int, val T) error {
+	if err := l.checkIndex(index, l.length+1); err != nil {
+		return err
+	}
+
+	switch index {
+	case 0:
+		l.PushFront(val)
+	case l.length:
+		l.PushBack(val)
+	default:
+		previous := l.node(index - 1)
+		previous.next = &listNode[T]{next: previous.next, data: val}
+		l.length++
+	}
+	return nil
+}
+
+// RemoveAt removes the value at index and returns it.
+func (l *List[T]) RemoveAt(index int) (T, error) {
+	if err := l.checkIndex(index, l.length); err != nil {
+		var t T
+		return t, err
+	}
+
+	var previous *listNode[T]
+	removed := l.head
+	if index == 0 {
+		l.head = removed.next
+	} else {
+		previous = l.node(index - 1)
+		removed = previous.next
+		previous.next = removed.next
+	}
+	if removed == l.tail {
+		l.tail = previous
+	}
+	l.length--
+	return removed.data, nil
+}
+
+// Find returns the first value for which match returns true.
+func (l *List[T]) Find(match func(val T) bool) (T, bool) {
+	for n := l.head; n != nil; n = n.next {
+		if match(n.data) {
+			return n.data, true
+		}
+	}
+	var t T
+	return t, false
+}
+
+// IndexFunc returns the index of the first value for which match returns true, or -1 if there is none.
+func (l *List[T]) IndexFunc(match func(val T) bool) int {
+	index := 0
+	for n := l.head; n != nil; n = n.next {
+		if match(n.data) {
+			return index
+		}
+		index++
+	}
+	return -1
+}
+
+// Each calls fn for every value in order. Walking stops as soon as fn returns false.
+func (l *List[T]) Each(fn func(index int, val T) bool) {
+	index := 0
+	for n := l.head; n != nil; n = n.next {
+		if !fn(index, n.data) {
+			return
+		}
+		index++
+	}
+}
+
+// ToSlice returns the values of the list. The slice of an empty list is empty, but not nil.
+func (l *List[T]) ToSlice() []T {
+	result := make([]T, 0, l.length)
+	for n := l.head; n != nil; n = n.next {
+		result = append(result, n.data)
+	}
+	return result
+}
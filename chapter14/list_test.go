@@ -0,0 +1,110 @@
+package chapter14
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	l := NewList[int]()
+	l.PushBack(2)
+	l.PushFront(1)
+	l.PushBack(4)
+	require.NoError(t, l.InsertAt(2, 3))
+	require.NoError(t, l.InsertAt(4, 5))
+	require.NoError(t, l.InsertAt(0, 0))
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, l.ToSlice())
+	assert.Equal(t, 6, l.Len())
+
+	v, err := l.Get(5)
+	require.NoError(t, err)
+	assert.Equal(t, 5, v)
+	require.NoError(t, l.Set(3, 30))
+	v, err = l.Get(3)
+	require.NoError(t, err)
+	assert.Equal(t, 30, v)
+
+	v, err = l.RemoveAt(5)
+	require.NoError(t, err)
+	assert.Equal(t, 5, v)
+	// The tail has to move back, so appending still works.
+	l.PushBack(6)
+	v, err = l.RemoveAt(0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, v)
+	v, err = l.RemoveAt(2)
+	require.NoError(t, err)
+	assert.Equal(t, 30, v)
+	assert.Equal(t, []int{1, 2, 4, 6}, l.ToSlice())
+	assert.Equal(t, 4, l.Len())
+}
+
+func TestListIndexErrors(t *testing.T) {
+	l := ListFromSlice([]string{"a", "b"})
+
+	_, err := l.Get(2)
+	assert.True(t, errors.Is(err, ErrIndexOutOfRange))
+	assert.EqualError(t, err, "index out of range: 2 is not in [0, 2)")
+	_, err = l.Get(-1)
+	assert.ErrorIs(t, err, ErrIndexOutOfRange)
+	_, err = l.RemoveAt(2)
+	assert.ErrorIs(t, err, ErrIndexOutOfRange)
+	assert.ErrorIs(t, l.InsertAt(3, "c"), ErrIndexOutOfRange)
+	assert.ErrorIs(t, l.Set(5, "c"), ErrIndexOutOfRange)
+	assert.Equal(t, []string{"a", "b"}, l.ToSlice())
+
+	empty := NewList[string]()
+	_, err = empty.RemoveAt(0)
+	assert.ErrorIs(t, err, ErrIndexOutOfRange)
+}
+
+func TestListRemoveLast(t *testing.T) {
+	l := ListFromSlice([]int{1})
+	v, err := l.RemoveAt(0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 0, l.Len())
+	assert.Equal(t, []int{}, l.ToSlice())
+
+	l.PushBack(2)
+	l.PushFront(1)
+	assert.Equal(t, []int{1, 2}, l.ToSlice())
+}
+
+func TestListFind(t *testing.T) {
+	l := ListFromSlice([]string{"apple", "banana", "blueberry", "cherry"})
+	var calls []string
+	v, ok := l.Find(func(s string) bool {
+		calls = append(calls, s)
+		return strings.HasPrefix(s, "b")
+	})
+	assert.True(t, ok)
+	assert.Equal(t, "banana", v)
+	// The list is walked once, up to the match.
+	assert.Equal(t, []string{"apple", "banana"}, calls)
+	assert.Equal(t, 3, l.IndexFunc(func(s string) bool { return s == "cherry" }))
+
+	_, ok = l.Find(func(s string) bool { return s == "kiwi" })
+	assert.False(t, ok)
+	assert.Equal(t, -1, l.IndexFunc(func(s string) bool { return s == "kiwi" }))
+
+	var seen []string
+	l.Each(func(i int, s string) bool {
+		seen = append(seen, s)
+		return i < 1
+	})
+	assert.Equal(t, []string{"apple", "banana"}, seen)
+}
+
+func TestListSliceRoundTrip(t *testing.T) {
+	for _, values := range [][]int{{}, {1}, {1, 2, 3}} {
+		l := ListFromSlice(values)
+		assert.Equal(t, len(values), l.Len())
+		assert.Equal(t, values, l.ToSlice())
+	}
+	assert.Equal(t, []int{}, ListFromSlice[int](nil).ToSlice())
+}
@@ -0,0 +1,222 @@
+package chapter14
+
+import "fmt"
+
+// AVLNode is a node in an AVL tree. Height is the number of nodes on the longest path down to a leaf.
+type AVLNode[T Number] struct {
+	Left   *AVLNode[T]
+	Right  *AVLNode[T]
+	Data   T
+	Height int
+}
+
+// AVLTree is a binary search tree which keeps the heights of the two subtrees of every node
+// within one of each other. Whenever an insert or delete breaks that, the tree is rotated back
+// into shape on the way up, so it never degenerates into a linked list like Leaf does with sorted input.
+type AVLTree[T Number] struct {
+	Root *AVLNode[T]
+	size int
+}
+
+// NewAVLTree creates an AVL tree from a list of numbers.
+func NewAVLTree[T Number](nums []T) *AVLTree[T] {
+	tree := &AVLTree[T]{}
+	for _, n := range nums {
+		tree.Insert(n)
+	}
+	return tree
+}
+
+func (n *AVLNode[T]) height() int {
+	if n == nil {
+		return 0
+	}
+	return n.Height
+}
+
+func (n *AVLNode[T]) update() {
+	n.Height = 1 + maxInt(n.Left.height(), n.Right.height())
+}
+
+func (n *AVLNode[T]) balanceFactor() int {
+	return n.Left.height() - n.Right.height()
+}
+
+func (n *AVLNode[T]) rotateRight() *AVLNode[T] {
+	left := n.Left
+	n.Left = left.Right
+	left.Right = n
+	n.update()
+	left.update()
+	return left
+}
+
+func (n *AVLNode[T]) rotateLeft() *AVLNode[T] {
+	right := n.Right
+	n.Right = right.Left
+	right.Left = n
+	n.update()
+	right.update()
+	return right
+}
+
+// rebalance fixes the height of the node and rotates it if the subtrees are too far apart.
+// It returns the new root of the subtree.
+func (n *AVLNode[T]) rebalance() *AVLNode[T] {
+	n.update()
+	switch bf := n.balanceFactor(); {
+	case bf > 1:
+		// Left-right case, which the first rotation turns into a left-left case.
+		if n.Left.balanceFactor() < 0 {
+			n.Left = n.Left.rotateLeft()
+		}
+		return n.rotateRight()
+	case bf < -1:
+		if n.Right.balanceFactor() > 0 {
+			n.Right = n.Right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
+// Insert adds a value to the tree. Duplicates are ignored, just like with Leaf.
+func (t *AVLTree[T]) Insert(val T) {
+	var added bool
+	t.Root, added = avlInsert(t.Root, val)
+	if added {
+		t.size++
+	}
+}
+
+func avlInsert[T Number](node *AVLNode[T], val T) (*AVLNode[T], bool) {
+	if node == nil {
+		return &AVLNode[T]{Data: val, Height: 1}, true
+	}
+
+	var added bool
+	switch {
+	case val < node.Data:
+		node.Left, added = avlInsert(node.Left, val)
+	case val > node.Data:
+		node.Right, added = avlInsert(node.Right, val)
+	default:
+		return node, false
+	}
+
+	return node.rebalance(), added
+}
+
+// Delete removes a value from the tree. It returns false if the value wasn't there.
+func (t *AVLTree[T]) Delete(val T) bool {
+	var deleted bool
+	t.Root, deleted = avlDelete(t.Root, val)
+	if deleted {
+		t.size--
+	}
+	return deleted
+}
+
+func avlDelete[T Number](node *AVLNode[T], val T) (*AVLNode[T], bool) {
+	if node == nil {
+		return nil, false
+	}
+
+	var deleted bool
+	switch {
+	case val < node.Data:
+		node.Left, deleted = avlDelete(node.Left, val)
+	case val > node.Data:
+		node.Right, deleted = avlDelete(node.Right, val)
+	default:
+		if node.Left == nil {
+			return node.Right, true
+		}
+		if node.Right == nil {
+			return node.Left, true
+		}
+		// Replace the value with its successor and delete the successor instead.
+		successor := node.Right
+		for successor.Left != nil {
+			successor = successor.Left
+		}
+		node.Data = successor.Data
+		node.Right, _ = avlDelete(node.Right, successor.Data)
+		deleted = true
+	}
+
+	return node.rebalance(), deleted
+}
+
+// Search returns the node holding val or nil if there is none.
+func (t *AVLTree[T]) Search(val T) *AVLNode[T] {
+	node := t.Root
+	for node != nil && node.Data != val {
+		if val < node.Data {
+			node = node.Left
+		} else {
+			node = node.Right
+		}
+	}
+	return node
+}
+
+// Len returns the number of values in the tree.
+func (t *AVLTree[T]) Len() int {
+	return t.size
+}
+
+// Height returns the number of levels of the tree.
+func (t *AVLTree[T]) Height() int {
+	return t.Root.height()
+}
+
+// Validate checks the ordering of the values, that every stored height is correct and that no
+// node is out of balance. It returns the first violation it finds.
+func (t *AVLTree[T]) Validate() error {
+	_, count, err := validateAVL(t.Root, nil, nil)
+	if err != nil {
+		return err
+	}
+	if count != t.size {
+		return fmt.Errorf("tree has %d nodes but its size is %d", count, t.size)
+	}
+	return nil
+}
+
+// validateAVL returns the height and node count of the subtree. min and max are the exclusive
+// bounds the values have to be within, nil meaning there is no bound.
+func validateAVL[T Number](node *AVLNode[T], min, max *T) (int, int, error) {
+	if node == nil {
+		return 0, 0, nil
+	}
+	if (min != nil && node.Data <= *min) || (max != nil && node.Data >= *max) {
+		return 0, 0, fmt.Errorf("node %v is out of order", node.Data)
+	}
+
+	leftHeight, leftCount, err := validateAVL(node.Left, min, &node.Data)
+	if err != nil {
+		return 0, 0, err
+	}
+	rightHeight, rightCount, err := validateAVL(node.Right, &node.Data, max)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	height := 1 + maxInt(leftHeight, rightHeight)
+	if node.Height != height {
+		return 0, 0, fmt.Errorf("node %v has height %d but should have %d", node.Data, node.Height, height)
+	}
+	if diff := leftHeight - rightHeight; diff > 1 || diff < -1 {
+		return 0, 0, fmt.Errorf("node %v is out of balance by %d", node.Data, diff)
+	}
+
+	return height, leftCount + rightCount + 1, nil
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
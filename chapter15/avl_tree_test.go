@@ -0,0 +1,73 @@
+package chapter14
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAVLTreeSortedInput(t *testing.T) {
+	nums := make([]int, 1000)
+	for i := range nums {
+		nums[i] = i
+	}
+	tree := NewAVLTree(nums)
+	require.NoError(t, tree.Validate())
+	assert.Equal(t, 1000, tree.Len())
+	// A perfectly balanced tree of 1000 nodes has 10 levels and an AVL tree is at most 1.44 times as high.
+	assert.LessOrEqual(t, tree.Height(), 14)
+	assert.Equal(t, 500, tree.Search(500).Data)
+	assert.Nil(t, tree.Search(1000))
+}
+
+func TestAVLTreeRotations(t *testing.T) {
+	// Left-right case.
+	tree := NewAVLTree([]int{30, 10, 20})
+	assert.Equal(t, &AVLNode[int]{
+		Data:   20,
+		Height: 2,
+		Left:   &AVLNode[int]{Data: 10, Height: 1},
+		Right:  &AVLNode[int]{Data: 30, Height: 1},
+	}, tree.Root)
+
+	// Right-left case.
+	tree = NewAVLTree([]int{10, 30, 20})
+	assert.Equal(t, 20, tree.Root.Data)
+	require.NoError(t, tree.Validate())
+}
+
+func TestAVLTreeDelete(t *testing.T) {
+	tree := NewAVLTree([]int{50, 25, 75, 10, 33, 4, 11, 30, 40, 56, 89, 52, 61, 82, 95})
+	assert.False(t, tree.Delete(1))
+	assert.True(t, tree.Delete(50))
+	assert.Nil(t, tree.Search(50))
+	require.NoError(t, tree.Validate())
+
+	r := rand.New(rand.NewSource(42))
+	for _, v := range r.Perm(100) {
+		tree.Insert(v + 100)
+	}
+	for _, v := range r.Perm(100) {
+		assert.True(t, tree.Delete(v+100))
+		require.NoError(t, tree.Validate())
+	}
+	assert.Equal(t, 14, tree.Len())
+}
+
+func TestAVLTreeValidate(t *testing.T) {
+	tree := NewAVLTree([]int{2, 1, 3})
+	tree.Root.Left.Data = 5
+	assert.EqualError(t, tree.Validate(), "node 5 is out of order")
+
+	tree = NewAVLTree([]int{2, 1, 3})
+	tree.Root.Height = 3
+	assert.EqualError(t, tree.Validate(), "node 2 has height 3 but should have 2")
+
+	tree = &AVLTree[int]{
+		Root: &AVLNode[int]{Data: 1, Height: 3, Right: &AVLNode[int]{Data: 2, Height: 2, Right: &AVLNode[int]{Data: 3, Height: 1}}},
+		size: 3,
+	}
+	assert.EqualError(t, tree.Validate(), "node 1 is out of balance by -2")
+}
@@ -9,9 +9,9 @@ type Number interface {
 
 // Leaf is a node in a binary search tree.
 type Leaf[T Number] struct {
-	Left  *Leaf[T]
-	Right *Leaf[T]
-	Data  T
+	Left  *Leaf[T] `json:"left,omitempty"`
+	Right *Leaf[T] `json:"right,omitempty"`
+	Data  T        `json:"data"`
 }
 
 func (l *Leaf[T]) Insert(val T) {
@@ -0,0 +1,518 @@
+package chapter14
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// A node is stored in a single page:
+//
+//	leaf:     kind (1 byte) | key count (uint16) | next leaf (uint64) | entries of key (int64), value length (uint16), value
+//	internal: kind (1 byte) | key count (uint16) | keys (int64 each) | children (uint64 each, one more than keys)
+const (
+	leafPage byte = iota + 1
+	internalPage
+)
+
+const (
+	nodeHeaderSize    = 1 + 2
+	leafHeaderSize    = nodeHeaderSize + 8
+	leafEntryOverhead = 8 + 2
+	bplusMetadataSize = 4 * 8
+	bplusMinimumOrder = 3
+)
+
+type bplusNode struct {
+	id       PageID
+	leaf     bool
+	keys     []int64
+	values   [][]byte
+	children []PageID
+	// next is the leaf after this one, which is what makes range scans cheap.
+	next PageID
+}
+
+// BPlusTree is a B+tree of int64 keys and byte slice values, kept in the pages of a PageStore.
+// Every node holds up to order-1 keys, internal nodes only hold keys for finding the way, and all
+// the values live in the leaves, which are linked together in key order.
+type BPlusTree struct {
+	store        PageStore
+	order        int
+	maxValueSize int
+	root         PageID
+	size         int
+}
+
+// OpenBPlusTree opens the tree kept in store, or creates a new tree with the given order if the store is
+// empty. The order is the largest number of children a node can have. It must be at least 3, and the
+// page size of the store limits how large it can be and how long the values can get.
+func OpenBPlusTree(store PageStore, order int) (*BPlusTree, error) {
+	metadata, err := store.Metadata()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &BPlusTree{store: store}
+	if len(metadata) == 0 {
+		t.order = order
+	} else {
+		if len(metadata) != bplusMetadataSize {
+			return nil, errors.New("store does not hold a B+tree")
+		}
+		t.root = PageID(binary.BigEndian.Uint64(metadata[0:8]))
+		t.order = int(binary.BigEndian.Uint64(metadata[8:16]))
+		t.size = int(binary.BigEndian.Uint64(metadata[16:24]))
+		if stored := int(binary.BigEndian.Uint64(metadata[24:32])); stored != store.PageSize() {
+			return nil, fmt.Errorf("tree was created with a page size of %d, the store has %d", stored, store.PageSize())
+		}
+	}
+
+	if t.order < bplusMinimumOrder {
+		return nil, fmt.Errorf("order %d is less than %d", t.order, bplusMinimumOrder)
+	}
+	if t.order-1 > math.MaxUint16 {
+		// The key count of a node has to fit into its uint16 header field.
+		return nil, fmt.Errorf("order %d is larger than %d", t.order, math.MaxUint16+1)
+	}
+	if nodeHeaderSize+(t.order-1)*8+t.order*8 > store.PageSize() {
+		return nil, fmt.Errorf("order %d is too large for a page size of %d", t.order, store.PageSize())
+	}
+	t.maxValueSize = (store.PageSize()-leafHeaderSize)/(t.order-1) - leafEntryOverhead
+	if t.maxValueSize > math.MaxUint16 {
+		// The length of a value has to fit into its uint16 prefix.
+		t.maxValueSize = math.MaxUint16
+	}
+	if t.maxValueSize < 1 {
+		return nil, fmt.Errorf("order %d leaves no room for values in a page size of %d", t.order, store.PageSize())
+	}
+
+	if t.root == 0 {
+		root, err := t.allocate(&bplusNode{leaf: true})
+		if err != nil {
+			return nil, err
+		}
+		t.root = root.id
+		if err := t.saveMetadata(); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+func (t *BPlusTree) saveMetadata() error {
+	metadata := make([]byte, 0, bplusMetadataSize)
+	metadata = binary.BigEndian.AppendUint64(metadata, uint64(t.root))
+	metadata = binary.BigEndian.AppendUint64(metadata, uint64(t.order))
+	metadata = binary.BigEndian.AppendUint64(metadata, uint64(t.size))
+	metadata = binary.BigEndian.AppendUint64(metadata, uint64(t.store.PageSize()))
+	return t.store.SetMetadata(metadata)
+}
+
+// MaxValueSize returns the longest value which fits into a leaf.
+func (t *BPlusTree) MaxValueSize() int {
+	return t.maxValueSize
+}
+
+// Len returns the number of keys in the tree.
+func (t *BPlusTree) Len() int {
+	return t.size
+}
+
+func (t *BPlusTree) minKeys() int {
+	return (t.order+1)/2 - 1
+}
+
+func (t *BPlusTree) allocate(n *bplusNode) (*bplusNode, error) {
+	id, err := t.store.Allocate()
+	if err != nil {
+		return nil, err
+	}
+	n.id = id
+	return n, t.write(n)
+}
+
+func (t *BPlusTree) write(n *bplusNode) error {
+	page := make([]byte, 0, t.store.PageSize())
+	if n.leaf {
+		page = append(page, leafPage)
+		page = binary.BigEndian.AppendUint16(page, uint16(len(n.keys)))
+		page = binary.BigEndian.AppendUint64(page, uint64(n.next))
+		for i, k := range n.keys {
+			page = binary.BigEndian.AppendUint64(page, uint64(k))
+			page = binary.BigEndian.AppendUint16(page, uint16(len(n.values[i])))
+			page = append(page, n.values[i]...)
+		}
+	} else {
+		page = append(page, internalPage)
+		page = binary.BigEndian.AppendUint16(page, uint16(len(n.keys)))
+		for _, k := range n.keys {
+			page = binary.BigEndian.AppendUint64(page, uint64(k))
+		}
+		for _, c := range n.children {
+			page = binary.BigEndian.AppendUint64(page, uint64(c))
+		}
+	}
+
+	return t.store.Write(n.id, page)
+}
+
+func (t *BPlusTree) read(id PageID) (*bplusNode, error) {
+	page, err := t.store.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	corrupted := fmt.Errorf("page %d does not hold a valid node", id)
+	if len(page) < leafHeaderSize {
+		return nil, corrupted
+	}
+	n := &bplusNode{id: id}
+	count := int(binary.BigEndian.Uint16(page[1:3]))
+
+	switch page[0] {
+	case leafPage:
+		n.leaf = true
+		n.next = PageID(binary.BigEndian.Uint64(page[3:11]))
+		offset := leafHeaderSize
+		for i := 0; i < count; i++ {
+			if offset+leafEntryOverhead > len(page) {
+				return nil, corrupted
+			}
+			n.keys = append(n.keys, int64(binary.BigEndian.Uint64(page[offset:])))
+			length := int(binary.BigEndian.Uint16(page[offset+8:]))
+			offset += leafEntryOverhead
+			if offset+length > len(page) {
+				return nil, corrupted
+			}
+			n.values = append(n.values, append([]byte{}, page[offset:offset+length]...))
+			offset += length
+		}
+	case internalPage:
+		if nodeHeaderSize+count*8+(count+1)*8 > len(page) {
+			return nil, corrupted
+		}
+		offset := nodeHeaderSize
+		for i := 0; i < count; i++ {
+			n.keys = append(n.keys, int64(binary.BigEndian.Uint64(page[offset:])))
+			offset += 8
+		}
+		for i := 0; i <= count; i++ {
+			n.children = append(n.children, PageID(binary.BigEndian.Uint64(page[offset:])))
+			offset += 8
+		}
+	default:
+		return nil, corrupted
+	}
+
+	return n, nil
+}
+
+// childIndex returns the index of the child of an internal node which leads to key. The keys of an
+// internal node are the smallest keys of the children to their right.
+func (n *bplusNode) childIndex(key int64) int {
+	return sort.Search(len(n.keys), func(i int) bool { return n.keys[i] > key })
+}
+
+// keyIndex returns where key is or should be in a leaf.
+func (n *bplusNode) keyIndex(key int64) (int, bool) {
+	i := sort.Search(len(n.keys), func(i int) bool { return n.keys[i] >= key })
+	return i, i < len(n.keys) && n.keys[i] == key
+}
+
+// findLeaf returns the leaf which holds key, if the key is in the tree at all.
+func (t *BPlusTree) findLeaf(key int64) (*bplusNode, error) {
+	n, err := t.read(t.root)
+	for err == nil && !n.leaf {
+		n, err = t.read(n.children[n.childIndex(key)])
+	}
+	return n, err
+}
+
+// Get returns the value stored for key.
+func (t *BPlusTree) Get(key int64) ([]byte, bool, error) {
+	leaf, err := t.findLeaf(key)
+	if err != nil {
+		return nil, false, err
+	}
+	i, ok := leaf.keyIndex(key)
+	if !ok {
+		return nil, false, nil
+	}
+	return leaf.values[i], true, nil
+}
+
+// Put sets the value for key, replacing the previous value if there was one.
+func (t *BPlusTree) Put(key int64, value []byte) error {
+	if len(value) > t.maxValueSize {
+		return fmt.Errorf("value of %d bytes is longer than the maximum of %d", len(value), t.maxValueSize)
+	}
+
+	split, added, err := t.put(t.root, key, value)
+	if err != nil {
+		return err
+	}
+	if split != nil {
+		// The root was split, so the tree grows a level.
+		root, err := t.allocate(&bplusNode{
+			keys:     []int64{split.key},
+			children: []PageID{t.root, split.right},
+		})
+		if err != nil {
+			return err
+		}
+		t.root = root.id
+	}
+	if added {
+		t.size++
+	}
+	if split != nil || added {
+		return t.saveMetadata()
+	}
+	return nil
+}
+
+// bplusSplit is what a node reports to its parent after splitting: the new node to its right and
+// the smallest key which belongs there.
+type bplusSplit struct {
+	key   int64
+	right PageID
+}
+
+func (t *BPlusTree) put(id PageID, key int64, value []byte) (*bplusSplit, bool, error) {
+	n, err := t.read(id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if n.leaf {
+		i, ok := n.keyIndex(key)
+		if ok {
+			n.values[i] = value
+			return nil, false, t.write(n)
+		}
+		n.keys = insertAt(n.keys, i, key)
+		n.values = insertAt(n.values, i, value)
+		split, err := t.splitIfFull(n)
+		return split, true, err
+	}
+
+	i := n.childIndex(key)
+	childSplit, added, err := t.put(n.children[i], key, value)
+	if err != nil || childSplit == nil {
+		return nil, added, err
+	}
+
+	n.keys = insertAt(n.keys, i, childSplit.key)
+	n.children = insertAt(n.children, i+1, childSplit.right)
+	split, err := t.splitIfFull(n)
+	return split, added, err
+}
+
+// splitIfFull writes the node, moving the upper half of it into a new node first if it has too many keys.
+func (t *BPlusTree) splitIfFull(n *bplusNode) (*bplusSplit, error) {
+	if len(n.keys) < t.order {
+		return nil, t.write(n)
+	}
+
+	mid := len(n.keys) / 2
+	right := &bplusNode{leaf: n.leaf}
+	var key int64
+	if n.leaf {
+		// The leaves keep every key, the parent just gets a copy of the first one on the right.
+		right.keys = append(right.keys, n.keys[mid:]...)
+		right.values = append(right.values, n.values[mid:]...)
+		n.keys, n.values = n.keys[:mid], n.values[:mid]
+		key = right.keys[0]
+	} else {
+		// The middle key moves up to the parent.
+		key = n.keys[mid]
+		right.keys = append(right.keys, n.keys[mid+1:]...)
+		right.children = append(right.children, n.children[mid+1:]...)
+		n.keys, n.children = n.keys[:mid], n.children[:mid+1]
+	}
+
+	if _, err := t.allocate(right); err != nil {
+		return nil, err
+	}
+	if n.leaf {
+		right.next, n.next = n.next, right.id
+		if err := t.write(right); err != nil {
+			return nil, err
+		}
+	}
+
+	return &bplusSplit{key: key, right: right.id}, t.write(n)
+}
+
+// Delete removes key. It returns false if the key wasn't there.
+func (t *BPlusTree) Delete(key int64) (bool, error) {
+	deleted, err := t.delete(t.root, key)
+	if err != nil || !deleted {
+		return false, err
+	}
+	t.size--
+
+	root, err := t.read(t.root)
+	if err != nil {
+		return true, err
+	}
+	if !root.leaf && len(root.keys) == 0 {
+		// The root lost its last key, so its only child takes over and the tree shrinks a level.
+		t.root = root.children[0]
+		if err := t.store.Free(root.id); err != nil {
+			return true, err
+		}
+	}
+
+	return true, t.saveMetadata()
+}
+
+func (t *BPlusTree) delete(id PageID, key int64) (bool, error) {
+	n, err := t.read(id)
+	if err != nil {
+		return false, err
+	}
+
+	if n.leaf {
+		i, ok := n.keyIndex(key)
+		if !ok {
+			return false, nil
+		}
+		n.keys = removeAt(n.keys, i)
+		n.values = removeAt(n.values, i)
+		return true, t.write(n)
+	}
+
+	i := n.childIndex(key)
+	deleted, err := t.delete(n.children[i], key)
+	if err != nil || !deleted {
+		return deleted, err
+	}
+
+	child, err := t.read(n.children[i])
+	if err != nil {
+		return true, err
+	}
+	if len(child.keys) >= t.minKeys() {
+		return true, nil
+	}
+
+	return true, t.rebalance(n, i, child)
+}
+
+// rebalance fixes the child at index i of the parent which has too few keys. It borrows a key from
+// a sibling if the sibling can spare one, and merges the two otherwise.
+func (t *BPlusTree) rebalance(parent *bplusNode, i int, child *bplusNode) error {
+	if i > 0 {
+		left, err := t.read(parent.children[i-1])
+		if err != nil {
+			return err
+		}
+		if len(left.keys) > t.minKeys() {
+			last := len(left.keys) - 1
+			if child.leaf {
+				child.keys = insertAt(child.keys, 0, left.keys[last])
+				child.values = insertAt(child.values, 0, left.values[last])
+				left.keys, left.values = left.keys[:last], left.values[:last]
+				parent.keys[i-1] = child.keys[0]
+			} else {
+				// Rotate through the parent: its key comes down, the largest key of the sibling goes up.
+				child.keys = insertAt(child.keys, 0, parent.keys[i-1])
+				child.children = insertAt(child.children, 0, left.children[last+1])
+				parent.keys[i-1] = left.keys[last]
+				left.keys, left.children = left.keys[:last], left.children[:last+1]
+			}
+			return t.writeAll(left, child, parent)
+		}
+		return t.merge(parent, i-1, left, child)
+	}
+
+	right, err := t.read(parent.children[i+1])
+	if err != nil {
+		return err
+	}
+	if len(right.keys) > t.minKeys() {
+		if child.leaf {
+			child.keys = append(child.keys, right.keys[0])
+			child.values = append(child.values, right.values[0])
+			right.keys, right.values = removeAt(right.keys, 0), removeAt(right.values, 0)
+			parent.keys[i] = right.keys[0]
+		} else {
+			child.keys = append(child.keys, parent.keys[i])
+			child.children = append(child.children, right.children[0])
+			parent.keys[i] = right.keys[0]
+			right.keys, right.children = removeAt(right.keys, 0), removeAt(right.children, 0)
+		}
+		return t.writeAll(right, child, parent)
+	}
+	return t.merge(parent, i, child, right)
+}
+
+// merge moves everything from right into left, its sibling at index i of the parent, and frees right.
+func (t *BPlusTree) merge(parent *bplusNode, i int, left, right *bplusNode) error {
+	if left.leaf {
+		left.keys = append(left.keys, right.keys...)
+		left.values = append(left.values, right.values...)
+		left.next = right.next
+	} else {
+		// The key separating the two comes down between them.
+		left.keys = append(append(left.keys, parent.keys[i]), right.keys...)
+		left.children = append(left.children, right.children...)
+	}
+	parent.keys = removeAt(parent.keys, i)
+	parent.children = removeAt(parent.children, i+1)
+
+	if err := t.store.Free(right.id); err != nil {
+		return err
+	}
+	return t.writeAll(left, parent)
+}
+
+func (t *BPlusTree) writeAll(nodes ...*bplusNode) error {
+	for _, n := range nodes {
+		if err := t.write(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Range calls fn for every key between lo and hi inclusive in ascending order. After finding the
+// first leaf it only follows the links between the leaves. Walking stops as soon as fn returns false.
+func (t *BPlusTree) Range(lo, hi int64, fn func(key int64, value []byte) bool) error {
+	leaf, err := t.findLeaf(lo)
+	if err != nil {
+		return err
+	}
+
+	i, _ := leaf.keyIndex(lo)
+	for {
+		for ; i < len(leaf.keys); i++ {
+			if leaf.keys[i] > hi || !fn(leaf.keys[i], leaf.values[i]) {
+				return nil
+			}
+		}
+		if leaf.next == 0 {
+			return nil
+		}
+		if leaf, err = t.read(leaf.next); err != nil {
+			return err
+		}
+		i = 0
+	}
+}
+
+func insertAt[T any](s []T, i int, v T) []T {
+	s = append(s, v)
+	copy(s[i+1:], s[i:])
+	s[i] = v
+	return s
+}
+
+func removeAt[T any](s []T, i int) []T {
+	return append(s[:i], s[i+1:]...)
+}
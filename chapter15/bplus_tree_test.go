@@ -0,0 +1,193 @@
+package chapter14
+
+import (
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkBPlusTree verifies that every node but the root has enough keys, that keys are ordered
+// and within the bounds set by the parent, and that all leaves are on the same level.
+func checkBPlusTree(t *testing.T, tree *BPlusTree) {
+	leafDepth := -1
+	var check func(id PageID, depth int, lo, hi *int64)
+	check = func(id PageID, depth int, lo, hi *int64) {
+		n, err := tree.read(id)
+		require.NoError(t, err)
+		if id != tree.root {
+			assert.GreaterOrEqual(t, len(n.keys), tree.minKeys())
+		}
+		assert.Less(t, len(n.keys), tree.order)
+		for i, k := range n.keys {
+			if i > 0 {
+				assert.Less(t, n.keys[i-1], k)
+			}
+			if lo != nil {
+				assert.GreaterOrEqual(t, k, *lo)
+			}
+			if hi != nil {
+				assert.Less(t, k, *hi)
+			}
+		}
+		if n.leaf {
+			if leafDepth == -1 {
+				leafDepth = depth
+			}
+			assert.Equal(t, leafDepth, depth)
+			return
+		}
+		require.Len(t, n.children, len(n.keys)+1)
+		for i, c := range n.children {
+			childLo, childHi := lo, hi
+			if i > 0 {
+				childLo = &n.keys[i-1]
+			}
+			if i < len(n.keys) {
+				childHi = &n.keys[i]
+			}
+			check(c, depth+1, childLo, childHi)
+		}
+	}
+	check(tree.root, 0, nil, nil)
+}
+
+func TestBPlusTreePutGet(t *testing.T) {
+	tree, err := OpenBPlusTree(NewMemoryPageStore(256), 4)
+	require.NoError(t, err)
+
+	r := rand.New(rand.NewSource(1))
+	for _, k := range r.Perm(500) {
+		require.NoError(t, tree.Put(int64(k), []byte(fmt.Sprint(k))))
+	}
+	checkBPlusTree(t, tree)
+	assert.Equal(t, 500, tree.Len())
+
+	require.NoError(t, tree.Put(42, []byte("answer")))
+	assert.Equal(t, 500, tree.Len())
+	v, ok, err := tree.Get(42)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("answer"), v)
+	v, ok, err = tree.Get(43)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("43"), v)
+	_, ok, err = tree.Get(500)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.Error(t, tree.Put(1, make([]byte, tree.MaxValueSize()+1)))
+}
+
+func TestBPlusTreeDelete(t *testing.T) {
+	for _, order := range []int{3, 4, 5, 8} {
+		t.Run(fmt.Sprint(order), func(t *testing.T) {
+			store := NewMemoryPageStore(512)
+			tree, err := OpenBPlusTree(store, order)
+			require.NoError(t, err)
+
+			r := rand.New(rand.NewSource(int64(order)))
+			expected := make(map[int64]string)
+			for _, k := range r.Perm(300) {
+				expected[int64(k)] = fmt.Sprint(k)
+				require.NoError(t, tree.Put(int64(k), []byte(fmt.Sprint(k))))
+			}
+			for _, k := range r.Perm(300)[:250] {
+				deleted, err := tree.Delete(int64(k))
+				require.NoError(t, err)
+				assert.True(t, deleted)
+				delete(expected, int64(k))
+				checkBPlusTree(t, tree)
+			}
+			deleted, err := tree.Delete(1000)
+			require.NoError(t, err)
+			assert.False(t, deleted)
+			assert.Equal(t, len(expected), tree.Len())
+
+			for k, want := range expected {
+				v, ok, err := tree.Get(k)
+				require.NoError(t, err)
+				assert.True(t, ok)
+				assert.Equal(t, want, string(v))
+			}
+
+			for k := range expected {
+				_, err := tree.Delete(k)
+				require.NoError(t, err)
+			}
+			checkBPlusTree(t, tree)
+			// Merged and collapsed pages are handed back, only the root leaf is left.
+			assert.Len(t, store.pages, len(store.free)+2)
+		})
+	}
+}
+
+func TestBPlusTreeRange(t *testing.T) {
+	tree, err := OpenBPlusTree(NewMemoryPageStore(256), 4)
+	require.NoError(t, err)
+	for k := int64(0); k < 100; k += 2 {
+		require.NoError(t, tree.Put(k, nil))
+	}
+
+	var keys []int64
+	require.NoError(t, tree.Range(9, 21, func(key int64, _ []byte) bool {
+		keys = append(keys, key)
+		return true
+	}))
+	assert.Equal(t, []int64{10, 12, 14, 16, 18, 20}, keys)
+
+	keys = nil
+	require.NoError(t, tree.Range(90, 1000, func(key int64, _ []byte) bool {
+		keys = append(keys, key)
+		return len(keys) < 3
+	}))
+	assert.Equal(t, []int64{90, 92, 94}, keys)
+}
+
+func TestBPlusTreeOrder(t *testing.T) {
+	_, err := OpenBPlusTree(NewMemoryPageStore(256), 2)
+	assert.Error(t, err)
+	_, err = OpenBPlusTree(NewMemoryPageStore(256), 100)
+	assert.Error(t, err)
+	// The page would be large enough, but the key count of a node has to fit into a uint16.
+	_, err = OpenBPlusTree(NewMemoryPageStore(1<<21), 1<<16+2)
+	assert.EqualError(t, err, "order 65538 is larger than 65536")
+}
+
+func TestBPlusTreeFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index")
+	store, err := OpenFilePageStore(path, 512)
+	require.NoError(t, err)
+	tree, err := OpenBPlusTree(store, 16)
+	require.NoError(t, err)
+	for k := int64(0); k < 1000; k++ {
+		require.NoError(t, tree.Put(k, []byte(fmt.Sprintf("value-%d", k))))
+	}
+	for k := int64(0); k < 1000; k += 3 {
+		_, err := tree.Delete(k)
+		require.NoError(t, err)
+	}
+	require.NoError(t, store.Close())
+
+	// The order stored in the file wins over the one passed in.
+	store, err = OpenFilePageStore(path, 512)
+	require.NoError(t, err)
+	defer store.Close()
+	tree, err = OpenBPlusTree(store, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 16, tree.order)
+	assert.Equal(t, 666, tree.Len())
+	checkBPlusTree(t, tree)
+
+	v, ok, err := tree.Get(500)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "value-500", string(v))
+	_, ok, err = tree.Get(501)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
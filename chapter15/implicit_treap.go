@@ -0,0 +1,213 @@
+package chapter14
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+type implicitNode[T any] struct {
+	left     *implicitNode[T]
+	right    *implicitNode[T]
+	value    T
+	priority int64
+	size     int
+	// reversed marks a subtree whose order still has to be flipped. It's only pushed down to the
+	// children when the subtree is visited, which makes Reverse logarithmic.
+	reversed bool
+}
+
+func (n *implicitNode[T]) len() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *implicitNode[T]) update() {
+	n.size = 1 + n.left.len() + n.right.len()
+}
+
+func (n *implicitNode[T]) push() {
+	if n == nil || !n.reversed {
+		return
+	}
+	n.left, n.right = n.right, n.left
+	if n.left != nil {
+		n.left.reversed = !n.left.reversed
+	}
+	if n.right != nil {
+		n.right.reversed = !n.right.reversed
+	}
+	n.reversed = false
+}
+
+// ImplicitTreap is a treap used as a sequence. There are no keys; the position of a node is the
+// number of nodes before it, which the subtree sizes give away. Splitting by position instead of
+// by key makes inserting, removing and reversing anywhere in the sequence logarithmic.
+type ImplicitTreap[T any] struct {
+	root   *implicitNode[T]
+	random *rand.Rand
+}
+
+// NewImplicitTreap creates an empty sequence. The seed makes the shape of the tree reproducible.
+func NewImplicitTreap[T any](seed int64) *ImplicitTreap[T] {
+	return &ImplicitTreap[T]{
+		random: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// splitAt splits the tree into the first count values and the rest.
+func splitAt[T any](n *implicitNode[T], count int) (*implicitNode[T], *implicitNode[T]) {
+	if n == nil {
+		return nil, nil
+	}
+	n.push()
+
+	if n.left.len() < count {
+		left, right := splitAt(n.right, count-n.left.len()-1)
+		n.right = left
+		n.update()
+		return n, right
+	}
+
+	left, right := splitAt(n.left, count)
+	n.left = right
+	n.update()
+	return left, n
+}
+
+func mergeImplicit[T any](left, right *implicitNode[T]) *implicitNode[T] {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+
+	if left.priority > right.priority {
+		left.push()
+		left.right = mergeImplicit(left.right, right)
+		left.update()
+		return left
+	}
+	right.push()
+	right.left = mergeImplicit(left, right.left)
+	right.update()
+	return right
+}
+
+// Len returns the number of values in the sequence.
+func (t *ImplicitTreap[T]) Len() int {
+	return t.root.len()
+}
+
+func (t *ImplicitTreap[T]) checkIndex(index, length int) error {
+	if index < 0 || index >= length {
+		return fmt.Errorf("index %d is out of range [0, %d)", index, length)
+	}
+	return nil
+}
+
+// InsertAt inserts a value so that it ends up at index. Inserting at Len appends the value.
+func (t *ImplicitTreap[T]) InsertAt(index int, value T) error {
+	if err := t.checkIndex(index, t.Len()+1); err != nil {
+		return err
+	}
+
+	left, right := splitAt(t.root, index)
+	node := &implicitNode[T]{value: value, priority: t.random.Int63(), size: 1}
+	t.root = mergeImplicit(mergeImplicit(left, node), right)
+	return nil
+}
+
+// Append adds a value to the end of the sequence.
+func (t *ImplicitTreap[T]) Append(value T) {
+	// Can't fail, the end of the sequence is always a valid index.
+	_ = t.InsertAt(t.Len(), value)
+}
+
+// RemoveAt removes the value at index and returns it.
+func (t *ImplicitTreap[T]) RemoveAt(index int) (T, error) {
+	if err := t.checkIndex(index, t.Len()); err != nil {
+		var v T
+		return v, err
+	}
+
+	left, rest := splitAt(t.root, index)
+	middle, right := splitAt(rest, 1)
+	t.root = mergeImplicit(left, right)
+	return middle.value, nil
+}
+
+func (t *ImplicitTreap[T]) node(index int) (*implicitNode[T], error) {
+	if err := t.checkIndex(index, t.Len()); err != nil {
+		return nil, err
+	}
+
+	n := t.root
+	for {
+		n.push()
+		left := n.left.len()
+		switch {
+		case index < left:
+			n = n.left
+		case index > left:
+			index -= left + 1
+			n = n.right
+		default:
+			return n, nil
+		}
+	}
+}
+
+// At returns the value at index.
+func (t *ImplicitTreap[T]) At(index int) (T, error) {
+	n, err := t.node(index)
+	if err != nil {
+		var v T
+		return v, err
+	}
+	return n.value, nil
+}
+
+// Set replaces the value at index.
+func (t *ImplicitTreap[T]) Set(index int, value T) error {
+	n, err := t.node(index)
+	if err != nil {
+		return err
+	}
+	n.value = value
+	return nil
+}
+
+// Reverse reverses the order of the values from index from up to but not including index to.
+func (t *ImplicitTreap[T]) Reverse(from, to int) error {
+	if from < 0 || to > t.Len() || from > to {
+		return fmt.Errorf("range [%d, %d) is out of range [0, %d)", from, to, t.Len())
+	}
+
+	left, rest := splitAt(t.root, from)
+	middle, right := splitAt(rest, to-from)
+	if middle != nil {
+		middle.reversed = !middle.reversed
+	}
+	t.root = mergeImplicit(mergeImplicit(left, middle), right)
+	return nil
+}
+
+// Values returns the sequence as a slice.
+func (t *ImplicitTreap[T]) Values() []T {
+	values := make([]T, 0, t.Len())
+	var visit func(n *implicitNode[T])
+	visit = func(n *implicitNode[T]) {
+		if n == nil {
+			return
+		}
+		n.push()
+		visit(n.left)
+		values = append(values, n.value)
+		visit(n.right)
+	}
+	visit(t.root)
+	return values
+}
@@ -0,0 +1,63 @@
+package chapter14
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImplicitTreapInsertRemove(t *testing.T) {
+	seq := NewImplicitTreap[string](1)
+	seq.Append("b")
+	seq.Append("d")
+	require.NoError(t, seq.InsertAt(0, "a"))
+	require.NoError(t, seq.InsertAt(2, "c"))
+	require.NoError(t, seq.InsertAt(4, "e"))
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, seq.Values())
+	assert.EqualError(t, seq.InsertAt(6, "x"), "index 6 is out of range [0, 6)")
+
+	v, err := seq.RemoveAt(1)
+	require.NoError(t, err)
+	assert.Equal(t, "b", v)
+	_, err = seq.RemoveAt(4)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"a", "c", "d", "e"}, seq.Values())
+	assert.Equal(t, 4, seq.Len())
+
+	v, err = seq.At(2)
+	require.NoError(t, err)
+	assert.Equal(t, "d", v)
+	require.NoError(t, seq.Set(2, "D"))
+	_, err = seq.At(-1)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"a", "c", "D", "e"}, seq.Values())
+}
+
+func TestImplicitTreapReverse(t *testing.T) {
+	seq := NewImplicitTreap[int](1)
+	var expected []int
+	for i := 0; i < 100; i++ {
+		seq.Append(i)
+		expected = append(expected, i)
+	}
+
+	reverse := func(from, to int) {
+		for i, j := from, to-1; i < j; i, j = i+1, j-1 {
+			expected[i], expected[j] = expected[j], expected[i]
+		}
+	}
+	for _, r := range [][2]int{{10, 50}, {0, 100}, {20, 30}, {25, 75}, {5, 5}} {
+		require.NoError(t, seq.Reverse(r[0], r[1]))
+		reverse(r[0], r[1])
+		assert.Equal(t, expected, seq.Values())
+	}
+
+	// Positions have to account for pending reversals too.
+	for i, want := range expected {
+		v, err := seq.At(i)
+		require.NoError(t, err)
+		assert.Equal(t, want, v)
+	}
+	assert.Error(t, seq.Reverse(50, 101))
+}
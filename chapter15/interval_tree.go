@@ -0,0 +1,238 @@
+package chapter14
+
+import "fmt"
+
+// Interval is a closed range of values, so both Low and High belong to it.
+type Interval[T Ordered] struct {
+	Low  T
+	High T
+}
+
+// Overlaps returns true if the two intervals share at least one point.
+func (i Interval[T]) Overlaps(other Interval[T]) bool {
+	return i.Low <= other.High && other.Low <= i.High
+}
+
+func (i Interval[T]) compare(other Interval[T]) int {
+	if c := Compare(i.Low, other.Low); c != 0 {
+		return c
+	}
+	return Compare(i.High, other.High)
+}
+
+// IntervalEntry is an interval stored in an IntervalTree together with its value.
+type IntervalEntry[T Ordered, V any] struct {
+	Interval Interval[T]
+	Value    V
+}
+
+type intervalNode[T Ordered, V any] struct {
+	left  *intervalNode[T, V]
+	right *intervalNode[T, V]
+	entry IntervalEntry[T, V]
+	// seq is the insertion order, which orders equal intervals so they can all be kept.
+	seq    uint64
+	height int
+	// max is the largest High in this subtree. If it's below the start of a query, nothing
+	// in the subtree can overlap with it and the whole subtree can be skipped.
+	max T
+}
+
+// IntervalTree stores intervals ordered by their Low end in an AVL tree where every node also
+// knows the largest High below it. Finding everything that overlaps a range then only visits
+// the subtrees which can have a hit. Every hit costs at most one walk down the tree, so a query is
+// O(log n) without hits and O(k log n) for k hits in the worst case.
+type IntervalTree[T Ordered, V any] struct {
+	root    *intervalNode[T, V]
+	size    int
+	nextSeq uint64
+}
+
+func NewIntervalTree[T Ordered, V any]() *IntervalTree[T, V] {
+	return &IntervalTree[T, V]{}
+}
+
+func (n *intervalNode[T, V]) nodeHeight() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *intervalNode[T, V]) update() {
+	n.height = 1 + maxInt(n.left.nodeHeight(), n.right.nodeHeight())
+	n.max = n.entry.Interval.High
+	if n.left != nil && n.left.max > n.max {
+		n.max = n.left.max
+	}
+	if n.right != nil && n.right.max > n.max {
+		n.max = n.right.max
+	}
+}
+
+func (n *intervalNode[T, V]) balanceFactor() int {
+	return n.left.nodeHeight() - n.right.nodeHeight()
+}
+
+func (n *intervalNode[T, V]) rotateRight() *intervalNode[T, V] {
+	left := n.left
+	n.left = left.right
+	left.right = n
+	n.update()
+	left.update()
+	return left
+}
+
+func (n *intervalNode[T, V]) rotateLeft() *intervalNode[T, V] {
+	right := n.right
+	n.right = right.left
+	right.left = n
+	n.update()
+	right.update()
+	return right
+}
+
+// rebalance works just like in AVLTree, but the rotations also keep max up to date.
+func (n *intervalNode[T, V]) rebalance() *intervalNode[T, V] {
+	n.update()
+	switch bf := n.balanceFactor(); {
+	case bf > 1:
+		if n.left.balanceFactor() < 0 {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case bf < -1:
+		if n.right.balanceFactor() > 0 {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
+// Insert stores an interval with a value. The same interval can be inserted any number of times, with
+// every value kept and returned by queries in insertion order. It returns an error if Low is greater
+// than High.
+func (t *IntervalTree[T, V]) Insert(interval Interval[T], value V) error {
+	if interval.Low > interval.High {
+		return fmt.Errorf("invalid interval [%v, %v]: low is greater than high", interval.Low, interval.High)
+	}
+
+	t.nextSeq++
+	t.root = insertInterval(t.root, &intervalNode[T, V]{
+		entry: IntervalEntry[T, V]{Interval: interval, Value: value},
+		seq:   t.nextSeq,
+	})
+	t.size++
+	return nil
+}
+
+// compareNode orders by interval, and equal intervals by insertion order.
+func compareNode[T Ordered](interval Interval[T], seq uint64, other Interval[T], otherSeq uint64) int {
+	if c := interval.compare(other); c != 0 {
+		return c
+	}
+	return Compare(seq, otherSeq)
+}
+
+func insertInterval[T Ordered, V any](node, added *intervalNode[T, V]) *intervalNode[T, V] {
+	if node == nil {
+		added.update()
+		return added
+	}
+
+	// Sequence numbers are unique, so the new node always goes into one of the subtrees.
+	if compareNode(added.entry.Interval, added.seq, node.entry.Interval, node.seq) < 0 {
+		node.left = insertInterval(node.left, added)
+	} else {
+		node.right = insertInterval(node.right, added)
+	}
+	return node.rebalance()
+}
+
+// Delete removes an interval. If it was inserted more than once, the oldest entry goes first.
+// It returns false if the interval wasn't there.
+func (t *IntervalTree[T, V]) Delete(interval Interval[T]) bool {
+	// Equal intervals are ordered by insertion, so the oldest is the leftmost one.
+	var oldest *intervalNode[T, V]
+	for n := t.root; n != nil; {
+		switch c := interval.compare(n.entry.Interval); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			oldest = n
+			n = n.left
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+
+	t.root = deleteInterval(t.root, interval, oldest.seq)
+	t.size--
+	return true
+}
+
+// deleteInterval removes the node with the given interval and sequence number, which has to exist.
+func deleteInterval[T Ordered, V any](node *intervalNode[T, V], interval Interval[T], seq uint64) *intervalNode[T, V] {
+	switch c := compareNode(interval, seq, node.entry.Interval, node.seq); {
+	case c < 0:
+		node.left = deleteInterval(node.left, interval, seq)
+	case c > 0:
+		node.right = deleteInterval(node.right, interval, seq)
+	default:
+		if node.left == nil {
+			return node.right
+		}
+		if node.right == nil {
+			return node.left
+		}
+		successor := node.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		node.entry, node.seq = successor.entry, successor.seq
+		node.right = deleteInterval(node.right, successor.entry.Interval, successor.seq)
+	}
+
+	return node.rebalance()
+}
+
+// Overlapping returns every stored interval which shares at least one point with [low, high],
+// ordered by their Low end.
+func (t *IntervalTree[T, V]) Overlapping(low, high T) []IntervalEntry[T, V] {
+	var hits []IntervalEntry[T, V]
+	query := Interval[T]{Low: low, High: high}
+	var visit func(n *intervalNode[T, V])
+	visit = func(n *intervalNode[T, V]) {
+		// Nothing down here reaches the query.
+		if n == nil || n.max < low {
+			return
+		}
+		visit(n.left)
+		if n.entry.Interval.Overlaps(query) {
+			hits = append(hits, n.entry)
+		}
+		// Everything on the right starts after this node, so if this one starts after
+		// the query, so does the rest.
+		if n.entry.Interval.Low <= high {
+			visit(n.right)
+		}
+	}
+	visit(t.root)
+
+	return hits
+}
+
+// Containing returns every stored interval which contains the point, ordered by their Low end.
+// This is also called a stabbing query.
+func (t *IntervalTree[T, V]) Containing(point T) []IntervalEntry[T, V] {
+	return t.Overlapping(point, point)
+}
+
+// Len returns the number of intervals in the tree.
+func (t *IntervalTree[T, V]) Len() int {
+	return t.size
+}
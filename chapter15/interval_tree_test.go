@@ -0,0 +1,112 @@
+package chapter14
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIntervalTree(t *testing.T) *IntervalTree[int, string] {
+	tree := NewIntervalTree[int, string]()
+	for name, interval := range map[string]Interval[int]{
+		"a": {Low: 15, High: 20},
+		"b": {Low: 10, High: 30},
+		"c": {Low: 17, High: 19},
+		"d": {Low: 5, High: 20},
+		"e": {Low: 12, High: 15},
+		"f": {Low: 30, High: 40},
+	} {
+		require.NoError(t, tree.Insert(interval, name))
+	}
+	return tree
+}
+
+func names(entries []IntervalEntry[int, string]) []string {
+	var result []string
+	for _, e := range entries {
+		result = append(result, e.Value)
+	}
+	return result
+}
+
+func TestIntervalTreeOverlapping(t *testing.T) {
+	tree := newTestIntervalTree(t)
+	assert.Equal(t, []string{"d", "b", "e", "a"}, names(tree.Overlapping(14, 16)))
+	assert.Equal(t, []string{"b", "f"}, names(tree.Overlapping(25, 35)))
+	assert.Equal(t, []string{"f"}, names(tree.Overlapping(40, 50)))
+	assert.Nil(t, tree.Overlapping(41, 50))
+	assert.Nil(t, tree.Overlapping(0, 4))
+}
+
+func TestIntervalTreeContaining(t *testing.T) {
+	tree := newTestIntervalTree(t)
+	assert.Equal(t, []string{"d", "b", "a"}, names(tree.Containing(20)))
+	assert.Equal(t, []string{"b", "f"}, names(tree.Containing(30)))
+	assert.Nil(t, tree.Containing(41))
+}
+
+func TestIntervalTreeInsertDelete(t *testing.T) {
+	tree := newTestIntervalTree(t)
+	assert.Error(t, tree.Insert(Interval[int]{Low: 2, High: 1}, "x"))
+	require.NoError(t, tree.Insert(Interval[int]{Low: 1, High: 2}, "z"))
+	assert.Equal(t, 7, tree.Len())
+	assert.Equal(t, []string{"z"}, names(tree.Containing(1)))
+
+	assert.False(t, tree.Delete(Interval[int]{Low: 15, High: 21}))
+	assert.True(t, tree.Delete(Interval[int]{Low: 10, High: 30}))
+	assert.Equal(t, 6, tree.Len())
+	assert.Equal(t, []string{"f"}, names(tree.Containing(30)))
+}
+
+func TestIntervalTreeDuplicates(t *testing.T) {
+	tree := newTestIntervalTree(t)
+	// Two bookings of the same slot are both kept and found, in the order they were made.
+	require.NoError(t, tree.Insert(Interval[int]{Low: 15, High: 20}, "z"))
+	require.NoError(t, tree.Insert(Interval[int]{Low: 15, High: 20}, "y"))
+	assert.Equal(t, 8, tree.Len())
+	assert.Equal(t, []string{"d", "b", "a", "z", "y", "c"}, names(tree.Containing(18)))
+
+	// The oldest one goes first.
+	assert.True(t, tree.Delete(Interval[int]{Low: 15, High: 20}))
+	assert.Equal(t, []string{"d", "b", "z", "y", "c"}, names(tree.Containing(18)))
+	assert.True(t, tree.Delete(Interval[int]{Low: 15, High: 20}))
+	assert.True(t, tree.Delete(Interval[int]{Low: 15, High: 20}))
+	assert.False(t, tree.Delete(Interval[int]{Low: 15, High: 20}))
+	assert.Equal(t, []string{"d", "b", "c"}, names(tree.Containing(18)))
+	assert.Equal(t, 5, tree.Len())
+}
+
+func TestIntervalTreeMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tree := NewIntervalTree[int, int]()
+	var intervals []Interval[int]
+	for len(intervals) < 500 {
+		// The small range makes sure some intervals are inserted more than once.
+		low := r.Intn(300)
+		interval := Interval[int]{Low: low, High: low + r.Intn(20)}
+		require.NoError(t, tree.Insert(interval, len(intervals)))
+		intervals = append(intervals, interval)
+	}
+	for _, interval := range intervals[:250] {
+		assert.True(t, tree.Delete(interval))
+	}
+	assert.Equal(t, 250, tree.Len())
+
+	for q := 0; q < 100; q++ {
+		low := r.Intn(300)
+		query := Interval[int]{Low: low, High: low + r.Intn(20)}
+		expected := make(map[Interval[int]]int)
+		for _, interval := range intervals[250:] {
+			if interval.Overlaps(query) {
+				expected[interval]++
+			}
+		}
+		got := make(map[Interval[int]]int)
+		for _, e := range tree.Overlapping(query.Low, query.High) {
+			got[e.Interval]++
+		}
+		assert.Equal(t, expected, got)
+	}
+}
@@ -0,0 +1,360 @@
+package chapter14
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// PageID identifies a page in a PageStore. The zero PageID is never handed out, so it can be used for "no page".
+type PageID uint64
+
+// PageStore is a storage of fixed size pages. Next to the pages a store keeps a small metadata blob,
+// which is where the user of the store can keep track of things like the root page of a tree.
+type PageStore interface {
+	// PageSize returns the size of every page in bytes.
+	PageSize() int
+	// Allocate returns a new zeroed page. Freed pages are reused before the store grows.
+	Allocate() (PageID, error)
+	// Read returns the content of a page. The returned slice is always PageSize long.
+	Read(id PageID) ([]byte, error)
+	// Write replaces the content of a page. Data shorter than a page is padded with zeroes.
+	Write(id PageID, data []byte) error
+	// Free gives a page back to the store. Reading, writing or freeing it again returns ErrInvalidPage
+	// until it's handed out by Allocate again.
+	Free(id PageID) error
+	// Metadata returns the metadata blob, which is empty for a new store.
+	Metadata() ([]byte, error)
+	// SetMetadata replaces the metadata blob.
+	SetMetadata(data []byte) error
+	Close() error
+}
+
+// ErrInvalidPage is returned when accessing a page which was never allocated or has been freed.
+var ErrInvalidPage = errors.New("invalid page")
+
+// MemoryPageStore keeps all pages in memory. It's useful for tests and for trees which don't have to
+// survive a restart.
+type MemoryPageStore struct {
+	pageSize int
+	// pages are indexed by PageID, so the first one is never used.
+	pages    [][]byte
+	free     []PageID
+	metadata []byte
+}
+
+func NewMemoryPageStore(pageSize int) *MemoryPageStore {
+	return &MemoryPageStore{
+		pageSize: pageSize,
+		pages:    [][]byte{nil},
+	}
+}
+
+func (m *MemoryPageStore) PageSize() int {
+	return m.pageSize
+}
+
+func (m *MemoryPageStore) Allocate() (PageID, error) {
+	page := make([]byte, m.pageSize)
+	if len(m.free) > 0 {
+		var id PageID
+		id, m.free = m.free[len(m.free)-1], m.free[:len(m.free)-1]
+		m.pages[id] = page
+		return id, nil
+	}
+
+	m.pages = append(m.pages, page)
+	return PageID(len(m.pages) - 1), nil
+}
+
+func (m *MemoryPageStore) page(id PageID) ([]byte, error) {
+	if id == 0 || id >= PageID(len(m.pages)) || m.pages[id] == nil {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidPage, id)
+	}
+	return m.pages[id], nil
+}
+
+func (m *MemoryPageStore) Read(id PageID) ([]byte, error) {
+	page, err := m.page(id)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{}, page...), nil
+}
+
+func (m *MemoryPageStore) Write(id PageID, data []byte) error {
+	page, err := m.page(id)
+	if err != nil {
+		return err
+	}
+	if len(data) > m.pageSize {
+		return fmt.Errorf("data of %d bytes does not fit a page of %d bytes", len(data), m.pageSize)
+	}
+	n := copy(page, data)
+	for i := n; i < len(page); i++ {
+		page[i] = 0
+	}
+	return nil
+}
+
+func (m *MemoryPageStore) Free(id PageID) error {
+	if _, err := m.page(id); err != nil {
+		return err
+	}
+	m.pages[id] = nil
+	m.free = append(m.free, id)
+	return nil
+}
+
+func (m *MemoryPageStore) Metadata() ([]byte, error) {
+	return append([]byte{}, m.metadata...), nil
+}
+
+func (m *MemoryPageStore) SetMetadata(data []byte) error {
+	m.metadata = append([]byte{}, data...)
+	return nil
+}
+
+func (m *MemoryPageStore) Close() error {
+	return nil
+}
+
+// The first page of a file store is its header:
+//
+//	magic "PGST" | version (1 byte) | page size (uint32) | page count (uint64) | first free page (uint64) |
+//	metadata length (uint16) | metadata
+//
+// Freed pages form a linked list, every free page holding the ID of the next one in its first 8 bytes.
+const (
+	pageStoreMagic      = "PGST"
+	pageStoreVersion    = 1
+	pageStoreHeaderSize = len(pageStoreMagic) + 1 + 4 + 8 + 8 + 2
+	// MinPageSize is the smallest page size a FilePageStore accepts.
+	MinPageSize = 128
+)
+
+// FilePageStore keeps pages in a file. Everything is written straight to the file, so the operating
+// system decides what stays in memory and the data can be a lot larger than RAM.
+type FilePageStore struct {
+	file      *os.File
+	pageSize  int
+	pageCount uint64
+	freeHead  PageID
+	// free holds every page on the free list, so freed pages can be told apart from allocated ones
+	// without reading them. It's rebuilt from the free list when the store is opened.
+	free     map[PageID]struct{}
+	metadata []byte
+}
+
+// OpenFilePageStore opens the store in the file at path or creates it if the file doesn't exist yet.
+// The page size is only used for new stores, an existing store keeps the page size it was created with.
+func OpenFilePageStore(path string, pageSize int) (*FilePageStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	s := &FilePageStore{file: file, pageSize: pageSize, pageCount: 1, free: make(map[PageID]struct{})}
+	if info.Size() == 0 {
+		if pageSize < MinPageSize {
+			file.Close()
+			return nil, fmt.Errorf("page size %d is smaller than the minimum of %d", pageSize, MinPageSize)
+		}
+		err = s.writeHeader()
+	} else {
+		err = s.readHeader()
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *FilePageStore) writeHeader() error {
+	header := make([]byte, 0, s.pageSize)
+	header = append(header, pageStoreMagic...)
+	header = append(header, pageStoreVersion)
+	header = binary.BigEndian.AppendUint32(header, uint32(s.pageSize))
+	header = binary.BigEndian.AppendUint64(header, s.pageCount)
+	header = binary.BigEndian.AppendUint64(header, uint64(s.freeHead))
+	header = binary.BigEndian.AppendUint16(header, uint16(len(s.metadata)))
+	header = append(header, s.metadata...)
+	header = header[:s.pageSize]
+
+	_, err := s.file.WriteAt(header, 0)
+	return err
+}
+
+func (s *FilePageStore) readHeader() error {
+	header := make([]byte, pageStoreHeaderSize)
+	if _, err := io.ReadFull(io.NewSectionReader(s.file, 0, int64(len(header))), header); err != nil {
+		return fmt.Errorf("failed to read page store header: %w", err)
+	}
+	if string(header[:len(pageStoreMagic)]) != pageStoreMagic {
+		return errors.New("not a page store file")
+	}
+	rest := header[len(pageStoreMagic):]
+	if rest[0] != pageStoreVersion {
+		return fmt.Errorf("unsupported page store version %d", rest[0])
+	}
+
+	s.pageSize = int(binary.BigEndian.Uint32(rest[1:5]))
+	s.pageCount = binary.BigEndian.Uint64(rest[5:13])
+	s.freeHead = PageID(binary.BigEndian.Uint64(rest[13:21]))
+	metadataLength := int(binary.BigEndian.Uint16(rest[21:23]))
+	if s.pageSize < MinPageSize || metadataLength > s.pageSize-pageStoreHeaderSize {
+		return errors.New("corrupted page store header")
+	}
+
+	s.metadata = make([]byte, metadataLength)
+	if _, err := s.file.ReadAt(s.metadata, int64(pageStoreHeaderSize)); err != nil {
+		return fmt.Errorf("failed to read page store metadata: %w", err)
+	}
+	return s.readFreeList()
+}
+
+func (s *FilePageStore) readFreeList() error {
+	for id := s.freeHead; id != 0; {
+		if _, ok := s.free[id]; ok {
+			return fmt.Errorf("corrupted page store: page %d is on the free list twice", id)
+		}
+		page, err := s.readPage(id)
+		if err != nil {
+			return fmt.Errorf("corrupted page store free list: %w", err)
+		}
+		s.free[id] = struct{}{}
+		id = PageID(binary.BigEndian.Uint64(page))
+	}
+	return nil
+}
+
+func (s *FilePageStore) PageSize() int {
+	return s.pageSize
+}
+
+func (s *FilePageStore) offset(id PageID) (int64, error) {
+	if id == 0 || uint64(id) >= s.pageCount {
+		return 0, fmt.Errorf("%w: %d", ErrInvalidPage, id)
+	}
+	return int64(id) * int64(s.pageSize), nil
+}
+
+// checkAllocated returns ErrInvalidPage if the page is out of range or has been freed.
+func (s *FilePageStore) checkAllocated(id PageID) error {
+	if _, ok := s.free[id]; ok {
+		return fmt.Errorf("%w: %d has been freed", ErrInvalidPage, id)
+	}
+	_, err := s.offset(id)
+	return err
+}
+
+func (s *FilePageStore) Allocate() (PageID, error) {
+	if s.freeHead != 0 {
+		id := s.freeHead
+		page, err := s.readPage(id)
+		if err != nil {
+			return 0, err
+		}
+		s.freeHead = PageID(binary.BigEndian.Uint64(page))
+		if err := s.writeHeader(); err != nil {
+			return 0, err
+		}
+		delete(s.free, id)
+		return id, s.writePage(id, nil)
+	}
+
+	id := PageID(s.pageCount)
+	s.pageCount++
+	if err := s.writePage(id, nil); err != nil {
+		s.pageCount--
+		return 0, err
+	}
+	return id, s.writeHeader()
+}
+
+func (s *FilePageStore) Read(id PageID) ([]byte, error) {
+	if err := s.checkAllocated(id); err != nil {
+		return nil, err
+	}
+	return s.readPage(id)
+}
+
+// readPage reads a page whether it's allocated or not.
+func (s *FilePageStore) readPage(id PageID) ([]byte, error) {
+	offset, err := s.offset(id)
+	if err != nil {
+		return nil, err
+	}
+	page := make([]byte, s.pageSize)
+	if _, err := s.file.ReadAt(page, offset); err != nil {
+		return nil, fmt.Errorf("failed to read page %d: %w", id, err)
+	}
+	return page, nil
+}
+
+func (s *FilePageStore) Write(id PageID, data []byte) error {
+	if err := s.checkAllocated(id); err != nil {
+		return err
+	}
+	return s.writePage(id, data)
+}
+
+// writePage writes a page whether it's allocated or not.
+func (s *FilePageStore) writePage(id PageID, data []byte) error {
+	offset, err := s.offset(id)
+	if err != nil {
+		return err
+	}
+	if len(data) > s.pageSize {
+		return fmt.Errorf("data of %d bytes does not fit a page of %d bytes", len(data), s.pageSize)
+	}
+	page := make([]byte, s.pageSize)
+	copy(page, data)
+	_, err = s.file.WriteAt(page, offset)
+	return err
+}
+
+func (s *FilePageStore) Free(id PageID) error {
+	if err := s.checkAllocated(id); err != nil {
+		return err
+	}
+	if err := s.writePage(id, binary.BigEndian.AppendUint64(nil, uint64(s.freeHead))); err != nil {
+		return err
+	}
+	s.freeHead = id
+	s.free[id] = struct{}{}
+	return s.writeHeader()
+}
+
+func (s *FilePageStore) Metadata() ([]byte, error) {
+	return append([]byte{}, s.metadata...), nil
+}
+
+// SetMetadata replaces the metadata, which has to fit into the header page and its uint16 length.
+func (s *FilePageStore) SetMetadata(data []byte) error {
+	if len(data) > s.pageSize-pageStoreHeaderSize || len(data) > math.MaxUint16 {
+		return fmt.Errorf("metadata of %d bytes does not fit the header", len(data))
+	}
+	s.metadata = append([]byte{}, data...)
+	return s.writeHeader()
+}
+
+// Close syncs the file to disk and closes it.
+func (s *FilePageStore) Close() error {
+	if err := s.file.Sync(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
@@ -0,0 +1,118 @@
+package chapter14
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPageStore(t *testing.T, store PageStore) {
+	first, err := store.Allocate()
+	require.NoError(t, err)
+	second, err := store.Allocate()
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.NotZero(t, first)
+
+	require.NoError(t, store.Write(first, []byte("hello")))
+	page, err := store.Read(first)
+	require.NoError(t, err)
+	assert.Len(t, page, store.PageSize())
+	assert.Equal(t, []byte("hello"), page[:5])
+	assert.Equal(t, byte(0), page[5])
+
+	assert.Error(t, store.Write(first, make([]byte, store.PageSize()+1)))
+	_, err = store.Read(100)
+	assert.True(t, errors.Is(err, ErrInvalidPage))
+
+	// Freed pages can't be used or freed again until they're allocated again.
+	require.NoError(t, store.Free(first))
+	assert.ErrorIs(t, store.Free(first), ErrInvalidPage)
+	_, err = store.Read(first)
+	assert.ErrorIs(t, err, ErrInvalidPage)
+	assert.ErrorIs(t, store.Write(first, []byte("x")), ErrInvalidPage)
+	assert.ErrorIs(t, store.Free(0), ErrInvalidPage)
+
+	// Freed pages are reused and come back zeroed.
+	reused, err := store.Allocate()
+	require.NoError(t, err)
+	assert.Equal(t, first, reused)
+	page, err = store.Read(reused)
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, store.PageSize()), page)
+
+	metadata, err := store.Metadata()
+	require.NoError(t, err)
+	assert.Empty(t, metadata)
+	require.NoError(t, store.SetMetadata([]byte("meta")))
+	metadata, err = store.Metadata()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("meta"), metadata)
+
+	// A page freed only once is handed out only once.
+	third, err := store.Allocate()
+	require.NoError(t, err)
+	assert.NotEqual(t, first, third)
+	assert.NotEqual(t, second, third)
+}
+
+func TestMemoryPageStore(t *testing.T) {
+	store := NewMemoryPageStore(256)
+	testPageStore(t, store)
+	require.NoError(t, store.Free(1))
+	_, err := store.Read(1)
+	assert.True(t, errors.Is(err, ErrInvalidPage))
+}
+
+func TestFilePageStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pages")
+	store, err := OpenFilePageStore(path, 256)
+	require.NoError(t, err)
+	testPageStore(t, store)
+	require.NoError(t, store.Close())
+
+	// Everything survives reopening, including the page size and the free list.
+	store, err = OpenFilePageStore(path, 512)
+	require.NoError(t, err)
+	assert.Equal(t, 256, store.PageSize())
+	metadata, err := store.Metadata()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("meta"), metadata)
+	require.NoError(t, store.Free(2))
+	require.NoError(t, store.Close())
+
+	// The freed pages are known again after reopening.
+	store, err = OpenFilePageStore(path, 256)
+	require.NoError(t, err)
+	assert.ErrorIs(t, store.Free(2), ErrInvalidPage)
+	_, err = store.Read(2)
+	assert.ErrorIs(t, err, ErrInvalidPage)
+	id, err := store.Allocate()
+	require.NoError(t, err)
+	assert.Equal(t, PageID(2), id)
+	require.NoError(t, store.Close())
+}
+
+func TestFilePageStoreInvalid(t *testing.T) {
+	dir := t.TempDir()
+	_, err := OpenFilePageStore(filepath.Join(dir, "small"), 16)
+	assert.Error(t, err)
+
+	path := filepath.Join(dir, "garbage")
+	require.NoError(t, os.WriteFile(path, []byte("definitely not a page store"), 0o644))
+	_, err = OpenFilePageStore(path, 256)
+	assert.EqualError(t, err, "not a page store file")
+}
+
+func TestFilePageStoreMetadataLimit(t *testing.T) {
+	// The page is large enough for more metadata than the uint16 length in the header can describe.
+	store, err := OpenFilePageStore(filepath.Join(t.TempDir(), "pages"), 1<<17)
+	require.NoError(t, err)
+	defer store.Close()
+	assert.Error(t, store.SetMetadata(make([]byte, 1<<16)))
+	assert.NoError(t, store.SetMetadata(make([]byte, 1<<16-1)))
+}
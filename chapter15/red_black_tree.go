@@ -0,0 +1,263 @@
+package chapter14
+
+import "fmt"
+
+// RedBlackNode is a node in a red-black tree. Red is the color of the link coming from the parent.
+type RedBlackNode[T Number] struct {
+	Left  *RedBlackNode[T]
+	Right *RedBlackNode[T]
+	Data  T
+	Red   bool
+}
+
+// RedBlackTree is a left-leaning red-black tree. Every path from the root down to a leaf has
+// the same number of black nodes and no red node has a red child, so the longest path is at most
+// twice as long as the shortest one. The left-leaning variant also only allows red left children,
+// which cuts down on the number of cases to handle.
+type RedBlackTree[T Number] struct {
+	Root *RedBlackNode[T]
+	size int
+}
+
+// NewRedBlackTree creates a red-black tree from a list of numbers.
+func NewRedBlackTree[T Number](nums []T) *RedBlackTree[T] {
+	tree := &RedBlackTree[T]{}
+	for _, n := range nums {
+		tree.Insert(n)
+	}
+	return tree
+}
+
+func (n *RedBlackNode[T]) isRed() bool {
+	return n != nil && n.Red
+}
+
+func (n *RedBlackNode[T]) rotateLeft() *RedBlackNode[T] {
+	right := n.Right
+	n.Right = right.Left
+	right.Left = n
+	right.Red = n.Red
+	n.Red = true
+	return right
+}
+
+func (n *RedBlackNode[T]) rotateRight() *RedBlackNode[T] {
+	left := n.Left
+	n.Left = left.Right
+	left.Right = n
+	left.Red = n.Red
+	n.Red = true
+	return left
+}
+
+func (n *RedBlackNode[T]) flipColors() {
+	n.Red = !n.Red
+	n.Left.Red = !n.Left.Red
+	n.Right.Red = !n.Right.Red
+}
+
+// fixUp restores the invariants on the way back up after an insert or a delete.
+func (n *RedBlackNode[T]) fixUp() *RedBlackNode[T] {
+	if n.Right.isRed() && !n.Left.isRed() {
+		n = n.rotateLeft()
+	}
+	if n.Left.isRed() && n.Left.Left.isRed() {
+		n = n.rotateRight()
+	}
+	if n.Left.isRed() && n.Right.isRed() {
+		n.flipColors()
+	}
+	return n
+}
+
+// moveRedLeft makes sure the left child or one of its children is red, so a node can be removed on the left.
+func (n *RedBlackNode[T]) moveRedLeft() *RedBlackNode[T] {
+	n.flipColors()
+	if n.Right.Left.isRed() {
+		n.Right = n.Right.rotateRight()
+		n = n.rotateLeft()
+		n.flipColors()
+	}
+	return n
+}
+
+// moveRedRight is moveRedLeft for the right side.
+func (n *RedBlackNode[T]) moveRedRight() *RedBlackNode[T] {
+	n.flipColors()
+	if n.Left.Left.isRed() {
+		n = n.rotateRight()
+		n.flipColors()
+	}
+	return n
+}
+
+// Insert adds a value to the tree. Duplicates are ignored, just like with Leaf.
+func (t *RedBlackTree[T]) Insert(val T) {
+	var added bool
+	t.Root, added = redBlackInsert(t.Root, val)
+	t.Root.Red = false
+	if added {
+		t.size++
+	}
+}
+
+func redBlackInsert[T Number](node *RedBlackNode[T], val T) (*RedBlackNode[T], bool) {
+	if node == nil {
+		return &RedBlackNode[T]{Data: val, Red: true}, true
+	}
+
+	var added bool
+	switch {
+	case val < node.Data:
+		node.Left, added = redBlackInsert(node.Left, val)
+	case val > node.Data:
+		node.Right, added = redBlackInsert(node.Right, val)
+	default:
+		return node, false
+	}
+
+	return node.fixUp(), added
+}
+
+// Delete removes a value from the tree. It returns false if the value wasn't there.
+func (t *RedBlackTree[T]) Delete(val T) bool {
+	// The delete below relies on the value being in the tree.
+	if t.Search(val) == nil {
+		return false
+	}
+
+	if !t.Root.Left.isRed() && !t.Root.Right.isRed() {
+		t.Root.Red = true
+	}
+	t.Root = redBlackDelete(t.Root, val)
+	if t.Root != nil {
+		t.Root.Red = false
+	}
+	t.size--
+	return true
+}
+
+func redBlackDelete[T Number](node *RedBlackNode[T], val T) *RedBlackNode[T] {
+	if val < node.Data {
+		if !node.Left.isRed() && !node.Left.Left.isRed() {
+			node = node.moveRedLeft()
+		}
+		node.Left = redBlackDelete(node.Left, val)
+		return node.fixUp()
+	}
+
+	if node.Left.isRed() {
+		node = node.rotateRight()
+	}
+	if val == node.Data && node.Right == nil {
+		return nil
+	}
+	if !node.Right.isRed() && !node.Right.Left.isRed() {
+		node = node.moveRedRight()
+	}
+	if val == node.Data {
+		// Replace the value with its successor and delete the successor instead.
+		successor := node.Right
+		for successor.Left != nil {
+			successor = successor.Left
+		}
+		node.Data = successor.Data
+		node.Right = redBlackDeleteMin(node.Right)
+	} else {
+		node.Right = redBlackDelete(node.Right, val)
+	}
+
+	return node.fixUp()
+}
+
+func redBlackDeleteMin[T Number](node *RedBlackNode[T]) *RedBlackNode[T] {
+	if node.Left == nil {
+		return nil
+	}
+	if !node.Left.isRed() && !node.Left.Left.isRed() {
+		node = node.moveRedLeft()
+	}
+	node.Left = redBlackDeleteMin(node.Left)
+	return node.fixUp()
+}
+
+// Search returns the node holding val or nil if there is none.
+func (t *RedBlackTree[T]) Search(val T) *RedBlackNode[T] {
+	node := t.Root
+	for node != nil && node.Data != val {
+		if val < node.Data {
+			node = node.Left
+		} else {
+			node = node.Right
+		}
+	}
+	return node
+}
+
+// Len returns the number of values in the tree.
+func (t *RedBlackTree[T]) Len() int {
+	return t.size
+}
+
+// Height returns the number of levels of the tree.
+func (t *RedBlackTree[T]) Height() int {
+	return redBlackHeight(t.Root)
+}
+
+func redBlackHeight[T Number](node *RedBlackNode[T]) int {
+	if node == nil {
+		return 0
+	}
+	return 1 + maxInt(redBlackHeight(node.Left), redBlackHeight(node.Right))
+}
+
+// Validate checks the ordering of the values and the color rules of the tree. It returns the first
+// violation it finds.
+func (t *RedBlackTree[T]) Validate() error {
+	if t.Root.isRed() {
+		return fmt.Errorf("root %v is red", t.Root.Data)
+	}
+	_, count, err := validateRedBlack(t.Root, nil, nil)
+	if err != nil {
+		return err
+	}
+	if count != t.size {
+		return fmt.Errorf("tree has %d nodes but its size is %d", count, t.size)
+	}
+	return nil
+}
+
+// validateRedBlack returns the number of black nodes on every path down from the node and the number
+// of nodes in the subtree. min and max are the exclusive bounds the values have to be within.
+func validateRedBlack[T Number](node *RedBlackNode[T], min, max *T) (int, int, error) {
+	if node == nil {
+		return 0, 0, nil
+	}
+	if (min != nil && node.Data <= *min) || (max != nil && node.Data >= *max) {
+		return 0, 0, fmt.Errorf("node %v is out of order", node.Data)
+	}
+	if node.Right.isRed() {
+		return 0, 0, fmt.Errorf("node %v has a red right child", node.Data)
+	}
+	if node.isRed() && node.Left.isRed() {
+		return 0, 0, fmt.Errorf("red node %v has a red child", node.Data)
+	}
+
+	leftBlack, leftCount, err := validateRedBlack(node.Left, min, &node.Data)
+	if err != nil {
+		return 0, 0, err
+	}
+	rightBlack, rightCount, err := validateRedBlack(node.Right, &node.Data, max)
+	if err != nil {
+		return 0, 0, err
+	}
+	if leftBlack != rightBlack {
+		return 0, 0, fmt.Errorf("node %v has %d black nodes on the left and %d on the right", node.Data, leftBlack, rightBlack)
+	}
+
+	black := leftBlack
+	if !node.isRed() {
+		black++
+	}
+	return black, leftCount + rightCount + 1, nil
+}
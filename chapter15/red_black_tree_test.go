@@ -0,0 +1,72 @@
+package chapter14
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedBlackTreeSortedInput(t *testing.T) {
+	nums := make([]int, 1000)
+	for i := range nums {
+		nums[i] = i
+	}
+	tree := NewRedBlackTree(nums)
+	require.NoError(t, tree.Validate())
+	assert.Equal(t, 1000, tree.Len())
+	// A red-black tree is at most twice as high as a perfectly balanced one.
+	assert.LessOrEqual(t, tree.Height(), 20)
+	assert.Equal(t, 500, tree.Search(500).Data)
+	assert.Nil(t, tree.Search(1000))
+}
+
+func TestRedBlackTreeInsert(t *testing.T) {
+	tree := NewRedBlackTree([]int{1, 2, 3})
+	assert.Equal(t, &RedBlackNode[int]{
+		Data:  2,
+		Left:  &RedBlackNode[int]{Data: 1},
+		Right: &RedBlackNode[int]{Data: 3},
+	}, tree.Root)
+	tree.Insert(2)
+	assert.Equal(t, 3, tree.Len())
+}
+
+func TestRedBlackTreeDelete(t *testing.T) {
+	tree := NewRedBlackTree([]int{50, 25, 75, 10, 33, 4, 11, 30, 40, 56, 89, 52, 61, 82, 95})
+	assert.False(t, tree.Delete(1))
+	assert.True(t, tree.Delete(50))
+	assert.Nil(t, tree.Search(50))
+	require.NoError(t, tree.Validate())
+
+	r := rand.New(rand.NewSource(42))
+	for _, v := range r.Perm(100) {
+		tree.Insert(v + 100)
+	}
+	for _, v := range r.Perm(100) {
+		assert.True(t, tree.Delete(v+100))
+		require.NoError(t, tree.Validate())
+	}
+	assert.Equal(t, 14, tree.Len())
+
+	for _, v := range []int{4, 10, 11, 25, 30, 33, 40, 52, 56, 61, 75, 82, 89, 95} {
+		tree.Delete(v)
+	}
+	assert.Nil(t, tree.Root)
+	assert.Equal(t, 0, tree.Len())
+}
+
+func TestRedBlackTreeValidate(t *testing.T) {
+	tree := NewRedBlackTree([]int{2, 1, 3})
+	tree.Root.Left.Data = 5
+	assert.EqualError(t, tree.Validate(), "node 5 is out of order")
+
+	tree = NewRedBlackTree([]int{2, 1, 3})
+	tree.Root.Right.Red = true
+	assert.EqualError(t, tree.Validate(), "node 2 has a red right child")
+
+	tree = NewRedBlackTree([]int{2, 1})
+	tree.Root.Left.Red = false
+	assert.EqualError(t, tree.Validate(), "node 2 has 1 black nodes on the left and 0 on the right")
+}
@@ -0,0 +1,194 @@
+package chapter14
+
+import "math/rand"
+
+// skipListMaxLevel is enough for 2^32 keys with the level chance of one half.
+const skipListMaxLevel = 32
+
+type skipListNode[K, V any] struct {
+	key   K
+	value V
+	// next holds the following node on every level this node is part of.
+	next []*skipListNode[K, V]
+}
+
+// SkipList is a sorted linked list with express lanes. Every node is on the bottom level, and every
+// node on a level is also on the next one up with a chance of one half. A search starts on the top
+// level and drops down a level whenever it would overshoot, which skips most of the list on average.
+type SkipList[K, V any] struct {
+	// head is a sentinel without a key, which is part of every level.
+	head    *skipListNode[K, V]
+	level   int
+	size    int
+	compare func(a, b K) int
+	random  *rand.Rand
+}
+
+// NewSkipList creates a SkipList for keys which can be compared with < and >. The seed makes the
+// levels of the nodes reproducible.
+func NewSkipList[K Ordered, V any](seed int64) *SkipList[K, V] {
+	return NewSkipListFunc[K, V](Compare[K], seed)
+}
+
+// NewSkipListFunc creates a SkipList which orders its keys using compare, see NewTreeMapFunc.
+func NewSkipListFunc[K, V any](compare func(a, b K) int, seed int64) *SkipList[K, V] {
+	return &SkipList[K, V]{
+		head:    &skipListNode[K, V]{next: make([]*skipListNode[K, V], skipListMaxLevel)},
+		level:   1,
+		compare: compare,
+		random:  rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (s *SkipList[K, V]) randomLevel() int {
+	level := 1
+	for level < skipListMaxLevel && s.random.Int63()&1 == 1 {
+		level++
+	}
+	return level
+}
+
+// predecessors returns the last node before key on every level.
+func (s *SkipList[K, V]) predecessors(key K) []*skipListNode[K, V] {
+	update := make([]*skipListNode[K, V], skipListMaxLevel)
+	n := s.head
+	for level := s.level - 1; level >= 0; level-- {
+		for n.next[level] != nil && s.compare(n.next[level].key, key) < 0 {
+			n = n.next[level]
+		}
+		update[level] = n
+	}
+	return update
+}
+
+// lastBefore returns the last node whose key is less than key, or the head if there is none.
+func (s *SkipList[K, V]) lastBefore(key K) *skipListNode[K, V] {
+	n := s.head
+	for level := s.level - 1; level >= 0; level-- {
+		for n.next[level] != nil && s.compare(n.next[level].key, key) < 0 {
+			n = n.next[level]
+		}
+	}
+	return n
+}
+
+// Put sets the value for key. It returns true if the key was already there and its value got replaced.
+func (s *SkipList[K, V]) Put(key K, value V) bool {
+	update := s.predecessors(key)
+	if n := update[0].next[0]; n != nil && s.compare(n.key, key) == 0 {
+		n.value = value
+		return true
+	}
+
+	level := s.randomLevel()
+	for ; s.level < level; s.level++ {
+		update[s.level] = s.head
+	}
+
+	node := &skipListNode[K, V]{key: key, value: value, next: make([]*skipListNode[K, V], level)}
+	for i := 0; i < level; i++ {
+		node.next[i] = update[i].next[i]
+		update[i].next[i] = node
+	}
+	s.size++
+	return false
+}
+
+// Get returns the value stored for key.
+func (s *SkipList[K, V]) Get(key K) (V, bool) {
+	n := s.lastBefore(key).next[0]
+	if n == nil || s.compare(n.key, key) != 0 {
+		var v V
+		return v, false
+	}
+	return n.value, true
+}
+
+// Delete removes key. It returns false if the key wasn't there.
+func (s *SkipList[K, V]) Delete(key K) bool {
+	update := s.predecessors(key)
+	n := update[0].next[0]
+	if n == nil || s.compare(n.key, key) != 0 {
+		return false
+	}
+
+	for i := range n.next {
+		update[i].next[i] = n.next[i]
+	}
+	for s.level > 1 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+	s.size--
+	return true
+}
+
+// Len returns the number of keys.
+func (s *SkipList[K, V]) Len() int {
+	return s.size
+}
+
+func skipListEntry[K, V any](n *skipListNode[K, V]) (K, V, bool) {
+	if n == nil {
+		var (
+			k K
+			v V
+		)
+		return k, v, false
+	}
+	return n.key, n.value, true
+}
+
+// Min returns the smallest key and its value. It returns false if the list is empty.
+func (s *SkipList[K, V]) Min() (K, V, bool) {
+	return skipListEntry(s.head.next[0])
+}
+
+// Max returns the largest key and its value. It returns false if the list is empty.
+func (s *SkipList[K, V]) Max() (K, V, bool) {
+	n := s.head
+	for level := s.level - 1; level >= 0; level-- {
+		for n.next[level] != nil {
+			n = n.next[level]
+		}
+	}
+	if n == s.head {
+		return skipListEntry[K, V](nil)
+	}
+	return skipListEntry(n)
+}
+
+// Floor returns the largest key which is less than or equal to key, and its value.
+func (s *SkipList[K, V]) Floor(key K) (K, V, bool) {
+	n := s.lastBefore(key)
+	if next := n.next[0]; next != nil && s.compare(next.key, key) == 0 {
+		return skipListEntry(next)
+	}
+	if n == s.head {
+		return skipListEntry[K, V](nil)
+	}
+	return skipListEntry(n)
+}
+
+// Ceiling returns the smallest key which is greater than or equal to key, and its value.
+func (s *SkipList[K, V]) Ceiling(key K) (K, V, bool) {
+	return skipListEntry(s.lastBefore(key).next[0])
+}
+
+// Range calls fn for every key between lo and hi inclusive in ascending order.
+// Walking stops as soon as fn returns false.
+func (s *SkipList[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	for n := s.lastBefore(lo).next[0]; n != nil && s.compare(n.key, hi) <= 0; n = n.next[0] {
+		if !fn(n.key, n.value) {
+			return
+		}
+	}
+}
+
+// InOrder calls fn for every entry in ascending order of keys. Walking stops as soon as fn returns false.
+func (s *SkipList[K, V]) InOrder(fn func(key K, value V) bool) {
+	for n := s.head.next[0]; n != nil; n = n.next[0] {
+		if !fn(n.key, n.value) {
+			return
+		}
+	}
+}
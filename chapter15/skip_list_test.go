@@ -0,0 +1,51 @@
+package chapter14
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func levels(s *SkipList[int, int]) []int {
+	var result []int
+	for n := s.head.next[0]; n != nil; n = n.next[0] {
+		result = append(result, len(n.next))
+	}
+	return result
+}
+
+func TestSkipListSeed(t *testing.T) {
+	build := func(seed int64) *SkipList[int, int] {
+		s := NewSkipList[int, int](seed)
+		for i := 0; i < 100; i++ {
+			s.Put(i, i)
+		}
+		return s
+	}
+	// The same seed gives the exact same list.
+	assert.Equal(t, levels(build(1)), levels(build(1)))
+	assert.NotEqual(t, levels(build(1)), levels(build(2)))
+}
+
+func TestSkipListLevels(t *testing.T) {
+	s := NewSkipList[int, int](1)
+	for i := 0; i < 1000; i++ {
+		s.Put(i, i)
+	}
+	// Every level should have roughly half the nodes of the one below it.
+	counts := make([]int, s.level)
+	for _, l := range levels(s) {
+		for i := 0; i < l; i++ {
+			counts[i]++
+		}
+	}
+	assert.Equal(t, 1000, counts[0])
+	assert.InDelta(t, 500, counts[1], 100)
+	assert.InDelta(t, 250, counts[2], 75)
+
+	for i := 0; i < 1000; i++ {
+		assert.True(t, s.Delete(i))
+	}
+	assert.Equal(t, 1, s.level)
+	assert.Equal(t, 0, s.Len())
+}
@@ -0,0 +1,304 @@
+package chapter14
+
+import "math/rand"
+
+// OrderedMap is the ordered API shared by TreeMap, Treap and SkipList, so they can be swapped
+// for one another and benchmarked side by side.
+type OrderedMap[K, V any] interface {
+	Put(key K, value V) bool
+	Get(key K) (V, bool)
+	Delete(key K) bool
+	Len() int
+	Min() (K, V, bool)
+	Max() (K, V, bool)
+	Floor(key K) (K, V, bool)
+	Ceiling(key K) (K, V, bool)
+	Range(lo, hi K, fn func(key K, value V) bool)
+	InOrder(fn func(key K, value V) bool)
+}
+
+var (
+	_ OrderedMap[int, int] = (*TreeMap[int, int])(nil)
+	_ OrderedMap[int, int] = (*Treap[int, int])(nil)
+	_ OrderedMap[int, int] = (*SkipList[int, int])(nil)
+)
+
+type treapNode[K, V any] struct {
+	left     *treapNode[K, V]
+	right    *treapNode[K, V]
+	key      K
+	value    V
+	priority int64
+	size     int
+}
+
+func (n *treapNode[K, V]) len() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *treapNode[K, V]) updateSize() {
+	n.size = 1 + n.left.len() + n.right.len()
+}
+
+// Treap is a binary search tree by its keys and a heap by random priorities given to every node.
+// The random priorities keep it balanced on average no matter what order the keys come in. Instead of
+// rotations, everything is built on two simple operations: split a tree into two by a key, and merge
+// two trees where all keys of one are smaller than all keys of the other.
+type Treap[K, V any] struct {
+	root    *treapNode[K, V]
+	compare func(a, b K) int
+	random  *rand.Rand
+}
+
+// NewTreap creates a Treap for keys which can be compared with < and >. The seed makes the shape of
+// the tree reproducible.
+func NewTreap[K Ordered, V any](seed int64) *Treap[K, V] {
+	return NewTreapFunc[K, V](Compare[K], seed)
+}
+
+// NewTreapFunc creates a Treap which orders its keys using compare, see NewTreeMapFunc.
+func NewTreapFunc[K, V any](compare func(a, b K) int, seed int64) *Treap[K, V] {
+	return &Treap[K, V]{
+		compare: compare,
+		random:  rand.New(rand.NewSource(seed)),
+	}
+}
+
+// split splits the tree into the keys before key and the rest. If inclusive is set, key itself
+// goes to the left as well.
+func (t *Treap[K, V]) split(n *treapNode[K, V], key K, inclusive bool) (*treapNode[K, V], *treapNode[K, V]) {
+	if n == nil {
+		return nil, nil
+	}
+
+	c := t.compare(n.key, key)
+	if c < 0 || (inclusive && c == 0) {
+		// The node and its left subtree go left, the right subtree has to be split further.
+		left, right := t.split(n.right, key, inclusive)
+		n.right = left
+		n.updateSize()
+		return n, right
+	}
+
+	left, right := t.split(n.left, key, inclusive)
+	n.left = right
+	n.updateSize()
+	return left, n
+}
+
+// mergeTreaps joins two trees where every key in left comes before every key in right. The node with
+// the higher priority becomes the root.
+func mergeTreaps[K, V any](left, right *treapNode[K, V]) *treapNode[K, V] {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+
+	if left.priority > right.priority {
+		left.right = mergeTreaps(left.right, right)
+		left.updateSize()
+		return left
+	}
+	right.left = mergeTreaps(left, right.left)
+	right.updateSize()
+	return right
+}
+
+func (t *Treap[K, V]) find(key K) *treapNode[K, V] {
+	n := t.root
+	for n != nil {
+		c := t.compare(key, n.key)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Put sets the value for key. It returns true if the key was already there and its value got replaced.
+func (t *Treap[K, V]) Put(key K, value V) bool {
+	if n := t.find(key); n != nil {
+		n.value = value
+		return true
+	}
+
+	left, right := t.split(t.root, key, false)
+	node := &treapNode[K, V]{key: key, value: value, priority: t.random.Int63(), size: 1}
+	t.root = mergeTreaps(mergeTreaps(left, node), right)
+	return false
+}
+
+// Get returns the value stored for key.
+func (t *Treap[K, V]) Get(key K) (V, bool) {
+	n := t.find(key)
+	if n == nil {
+		var v V
+		return v, false
+	}
+	return n.value, true
+}
+
+// Delete removes key. It returns false if the key wasn't there.
+func (t *Treap[K, V]) Delete(key K) bool {
+	left, rest := t.split(t.root, key, false)
+	middle, right := t.split(rest, key, true)
+	t.root = mergeTreaps(left, right)
+	return middle != nil
+}
+
+// Len returns the number of keys.
+func (t *Treap[K, V]) Len() int {
+	return t.root.len()
+}
+
+func treapEntry[K, V any](n *treapNode[K, V]) (K, V, bool) {
+	if n == nil {
+		var (
+			k K
+			v V
+		)
+		return k, v, false
+	}
+	return n.key, n.value, true
+}
+
+// Min returns the smallest key and its value. It returns false if the treap is empty.
+func (t *Treap[K, V]) Min() (K, V, bool) {
+	n := t.root
+	for n != nil && n.left != nil {
+		n = n.left
+	}
+	return treapEntry(n)
+}
+
+// Max returns the largest key and its value. It returns false if the treap is empty.
+func (t *Treap[K, V]) Max() (K, V, bool) {
+	n := t.root
+	for n != nil && n.right != nil {
+		n = n.right
+	}
+	return treapEntry(n)
+}
+
+// Floor returns the largest key which is less than or equal to key, and its value.
+func (t *Treap[K, V]) Floor(key K) (K, V, bool) {
+	var found *treapNode[K, V]
+	n := t.root
+	for n != nil {
+		c := t.compare(key, n.key)
+		if c == 0 {
+			return treapEntry(n)
+		}
+		if c < 0 {
+			n = n.left
+		} else {
+			found = n
+			n = n.right
+		}
+	}
+	return treapEntry(found)
+}
+
+// Ceiling returns the smallest key which is greater than or equal to key, and its value.
+func (t *Treap[K, V]) Ceiling(key K) (K, V, bool) {
+	var found *treapNode[K, V]
+	n := t.root
+	for n != nil {
+		c := t.compare(key, n.key)
+		if c == 0 {
+			return treapEntry(n)
+		}
+		if c > 0 {
+			n = n.right
+		} else {
+			found = n
+			n = n.left
+		}
+	}
+	return treapEntry(found)
+}
+
+// Rank returns the number of keys which are less than key.
+func (t *Treap[K, V]) Rank(key K) int {
+	rank := 0
+	n := t.root
+	for n != nil {
+		c := t.compare(key, n.key)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			rank += n.left.len() + 1
+			n = n.right
+		default:
+			return rank + n.left.len()
+		}
+	}
+	return rank
+}
+
+// Select returns the key with the given rank, counting from 0.
+func (t *Treap[K, V]) Select(rank int) (K, V, bool) {
+	return treapEntry(selectTreapNode(t.root, rank))
+}
+
+func selectTreapNode[K, V any](n *treapNode[K, V], rank int) *treapNode[K, V] {
+	for n != nil {
+		left := n.left.len()
+		switch {
+		case rank < left:
+			n = n.left
+		case rank > left:
+			rank -= left + 1
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Range calls fn for every key between lo and hi inclusive in ascending order.
+// Walking stops as soon as fn returns false.
+func (t *Treap[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	var visit func(n *treapNode[K, V]) bool
+	visit = func(n *treapNode[K, V]) bool {
+		if n == nil {
+			return true
+		}
+		fromLo, toHi := t.compare(lo, n.key), t.compare(hi, n.key)
+		if fromLo < 0 && !visit(n.left) {
+			return false
+		}
+		if fromLo <= 0 && toHi >= 0 && !fn(n.key, n.value) {
+			return false
+		}
+		if toHi > 0 {
+			return visit(n.right)
+		}
+		return true
+	}
+	visit(t.root)
+}
+
+// InOrder calls fn for every entry in ascending order of keys. Walking stops as soon as fn returns false.
+func (t *Treap[K, V]) InOrder(fn func(key K, value V) bool) {
+	var visit func(n *treapNode[K, V]) bool
+	visit = func(n *treapNode[K, V]) bool {
+		if n == nil {
+			return true
+		}
+		return visit(n.left) && fn(n.key, n.value) && visit(n.right)
+	}
+	visit(t.root)
+}
@@ -0,0 +1,159 @@
+package chapter14
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func orderedMaps() map[string]func() OrderedMap[int, int] {
+	return map[string]func() OrderedMap[int, int]{
+		"TreeMap":  func() OrderedMap[int, int] { return NewTreeMap[int, int]() },
+		"Treap":    func() OrderedMap[int, int] { return NewTreap[int, int](1) },
+		"SkipList": func() OrderedMap[int, int] { return NewSkipList[int, int](1) },
+	}
+}
+
+// TestOrderedMaps runs the same operations against every implementation and compares them with
+// a sorted slice.
+func TestOrderedMaps(t *testing.T) {
+	for name, newMap := range orderedMaps() {
+		t.Run(name, func(t *testing.T) {
+			m := newMap()
+			_, _, ok := m.Min()
+			assert.False(t, ok)
+			_, _, ok = m.Max()
+			assert.False(t, ok)
+
+			r := rand.New(rand.NewSource(1))
+			expected := make(map[int]int)
+			for i := 0; i < 500; i++ {
+				k := r.Intn(1000) * 2
+				_, existed := expected[k]
+				assert.Equal(t, existed, m.Put(k, i))
+				expected[k] = i
+			}
+			for i := 0; i < 200; i++ {
+				k := r.Intn(1000) * 2
+				_, existed := expected[k]
+				assert.Equal(t, existed, m.Delete(k))
+				delete(expected, k)
+			}
+			assert.Equal(t, len(expected), m.Len())
+
+			keys := make([]int, 0, len(expected))
+			for k, v := range expected {
+				keys = append(keys, k)
+				got, ok := m.Get(k)
+				assert.True(t, ok)
+				assert.Equal(t, v, got)
+			}
+			sort.Ints(keys)
+			_, ok = m.Get(1)
+			assert.False(t, ok)
+
+			var inOrder []int
+			m.InOrder(func(k, _ int) bool {
+				inOrder = append(inOrder, k)
+				return true
+			})
+			assert.Equal(t, keys, inOrder)
+
+			k, _, _ := m.Min()
+			assert.Equal(t, keys[0], k)
+			k, _, _ = m.Max()
+			assert.Equal(t, keys[len(keys)-1], k)
+
+			k, _, ok = m.Floor(keys[10] + 1)
+			assert.True(t, ok)
+			assert.Equal(t, keys[10], k)
+			_, _, ok = m.Floor(keys[0] - 1)
+			assert.False(t, ok)
+			k, _, ok = m.Ceiling(keys[10] + 1)
+			assert.True(t, ok)
+			assert.Equal(t, keys[11], k)
+			_, _, ok = m.Ceiling(keys[len(keys)-1] + 1)
+			assert.False(t, ok)
+
+			var inRange []int
+			m.Range(keys[5], keys[15], func(k, _ int) bool {
+				inRange = append(inRange, k)
+				return true
+			})
+			assert.Equal(t, keys[5:16], inRange)
+		})
+	}
+}
+
+func TestTreapRankSelect(t *testing.T) {
+	tr := NewTreap[int, string](7)
+	for _, v := range rand.New(rand.NewSource(1)).Perm(50) {
+		tr.Put(v*2, fmt.Sprint(v))
+	}
+	for i := 0; i < 50; i++ {
+		k, v, ok := tr.Select(i)
+		assert.True(t, ok)
+		assert.Equal(t, i*2, k)
+		assert.Equal(t, fmt.Sprint(i), v)
+		assert.Equal(t, i, tr.Rank(k))
+	}
+	assert.Equal(t, 6, tr.Rank(11))
+	_, _, ok := tr.Select(50)
+	assert.False(t, ok)
+}
+
+func TestTreapHeapOrder(t *testing.T) {
+	tr := NewTreap[int, int](3)
+	for i := 0; i < 1000; i++ {
+		tr.Put(i, i)
+	}
+	var check func(n *treapNode[int, int]) int
+	check = func(n *treapNode[int, int]) int {
+		if n == nil {
+			return 0
+		}
+		if n.left != nil {
+			assert.GreaterOrEqual(t, n.priority, n.left.priority)
+		}
+		if n.right != nil {
+			assert.GreaterOrEqual(t, n.priority, n.right.priority)
+		}
+		height := 1 + maxInt(check(n.left), check(n.right))
+		assert.Equal(t, 1+n.left.len()+n.right.len(), n.size)
+		return height
+	}
+	// Even with sorted input the tree stays shallow. The expected height is about 3 log n.
+	assert.Less(t, check(tr.root), 40)
+}
+
+func benchmarkOrderedMap(b *testing.B, newMap func() OrderedMap[int, int], keys []int) {
+	for i := 0; i < b.N; i++ {
+		m := newMap()
+		for _, k := range keys {
+			m.Put(k, k)
+		}
+		for _, k := range keys {
+			m.Get(k)
+		}
+		for _, k := range keys {
+			m.Delete(k)
+		}
+	}
+}
+
+func BenchmarkOrderedMaps(b *testing.B) {
+	inputs := map[string][]int{
+		"sorted": sortedInput(),
+		"random": randomInput(),
+	}
+	for name, newMap := range orderedMaps() {
+		for input, keys := range inputs {
+			b.Run(name+"/"+input, func(b *testing.B) {
+				benchmarkOrderedMap(b, newMap, keys)
+			})
+		}
+	}
+}
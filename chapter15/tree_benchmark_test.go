@@ -0,0 +1,54 @@
+package chapter14
+
+import (
+	"math/rand"
+	"testing"
+)
+
+const benchmarkTreeSize = 5000
+
+func sortedInput() []int {
+	nums := make([]int, benchmarkTreeSize)
+	for i := range nums {
+		nums[i] = i
+	}
+	return nums
+}
+
+func randomInput() []int {
+	return rand.New(rand.NewSource(1)).Perm(benchmarkTreeSize)
+}
+
+// benchmarkTree builds a tree from the input and then searches for every value in it.
+func benchmarkTree(b *testing.B, nums []int, build func([]int) func(int) bool) {
+	for i := 0; i < b.N; i++ {
+		search := build(nums)
+		for _, n := range nums {
+			if !search(n) {
+				b.Fatalf("%d not found", n)
+			}
+		}
+	}
+}
+
+func buildLeaf(nums []int) func(int) bool {
+	tree := NewBinarySearchTree(nums)
+	return func(n int) bool { return Search(tree, n) != nil }
+}
+
+func buildAVL(nums []int) func(int) bool {
+	tree := NewAVLTree(nums)
+	return func(n int) bool { return tree.Search(n) != nil }
+}
+
+func buildRedBlack(nums []int) func(int) bool {
+	tree := NewRedBlackTree(nums)
+	return func(n int) bool { return tree.Search(n) != nil }
+}
+
+func BenchmarkLeafSorted(b *testing.B)     { benchmarkTree(b, sortedInput(), buildLeaf) }
+func BenchmarkLeafRandom(b *testing.B)     { benchmarkTree(b, randomInput(), buildLeaf) }
+func BenchmarkAVLSorted(b *testing.B)      { benchmarkTree(b, sortedInput(), buildAVL) }
+func BenchmarkAVLRandom(b *testing.B)      { benchmarkTree(b, randomInput(), buildAVL) }
+func BenchmarkRedBlackSorted(b *testing.B) { benchmarkTree(b, sortedInput(), buildRedBlack) }
+func BenchmarkRedBlackRandom(b *testing.B) { benchmarkTree(b, randomInput(), buildRedBlack) }
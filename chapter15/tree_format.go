@@ -0,0 +1,170 @@
+package chapter14
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Equal returns true if both trees have the same shape and the same values in the same places.
+func Equal[T Number](a, b *Leaf[T]) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Data == b.Data && Equal(a.Left, b.Left) && Equal(a.Right, b.Right)
+}
+
+// SameValues returns true if both trees hold the same values, no matter how they are arranged.
+func SameValues[T Number](a, b *Leaf[T]) bool {
+	var left, right []T
+	left = collectInOrder(a, left)
+	right = collectInOrder(b, right)
+	if len(left) != len(right) {
+		return false
+	}
+	for i := range left {
+		if left[i] != right[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func collectInOrder[T Number](l *Leaf[T], values []T) []T {
+	if l == nil {
+		return values
+	}
+	values = collectInOrder(l.Left, values)
+	values = append(values, l.Data)
+	return collectInOrder(l.Right, values)
+}
+
+// WritePreOrder writes the values of the tree in pre-order separated by spaces. For a binary search
+// tree that's enough to rebuild the exact same tree, since the first value is always the root and
+// the values smaller than it make up the left subtree.
+func WritePreOrder[T Number](w io.Writer, l *Leaf[T]) error {
+	var values []string
+	var visit func(l *Leaf[T])
+	visit = func(l *Leaf[T]) {
+		if l == nil {
+			return
+		}
+		values = append(values, fmt.Sprint(l.Data))
+		visit(l.Left)
+		visit(l.Right)
+	}
+	visit(l)
+
+	_, err := io.WriteString(w, strings.Join(values, " "))
+	return err
+}
+
+// ReadPreOrder rebuilds a tree written by WritePreOrder. It returns an error if the values can't be
+// parsed or they aren't the pre-order of a binary search tree.
+func ReadPreOrder[T Number](r io.Reader) (*Leaf[T], error) {
+	var values []T
+	for {
+		var v T
+		_, err := fmt.Fscan(r, &v)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read value %d: %w", len(values), err)
+		}
+		values = append(values, v)
+	}
+
+	index := 0
+	// build takes values for as long as they fit between the bounds. nil means there is no bound.
+	var build func(min, max *T) *Leaf[T]
+	build = func(min, max *T) *Leaf[T] {
+		if index == len(values) {
+			return nil
+		}
+		v := values[index]
+		if (min != nil && v <= *min) || (max != nil && v >= *max) {
+			return nil
+		}
+		index++
+		l := &Leaf[T]{Data: v}
+		l.Left = build(min, &l.Data)
+		l.Right = build(&l.Data, max)
+		return l
+	}
+	root := build(nil, nil)
+
+	if index != len(values) {
+		return nil, fmt.Errorf("value %v at %d is not in binary search tree pre-order", values[index], index)
+	}
+	return root, nil
+}
+
+// Render writes the tree as ASCII art with every child marked as left (L) or right (R).
+//
+//	50
+//	├── L: 25
+//	│   └── R: 33
+//	└── R: 75
+func Render[T Number](w io.Writer, l *Leaf[T]) error {
+	if l == nil {
+		return nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%v\n", l.Data)
+	renderChildren(&sb, l, "")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func renderChildren[T Number](sb *strings.Builder, l *Leaf[T], indent string) {
+	type child struct {
+		side string
+		leaf *Leaf[T]
+	}
+	var children []child
+	if l.Left != nil {
+		children = append(children, child{side: "L", leaf: l.Left})
+	}
+	if l.Right != nil {
+		children = append(children, child{side: "R", leaf: l.Right})
+	}
+
+	for i, c := range children {
+		branch, next := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, next = "└── ", "    "
+		}
+		fmt.Fprintf(sb, "%s%s%s: %v\n", indent, branch, c.side, c.leaf.Data)
+		renderChildren(sb, c.leaf, indent+next)
+	}
+}
+
+// WriteDOT writes the tree in the Graphviz DOT language, which can be turned into an image using
+// `dot -Tpng`. Edges are labelled with the side of the child.
+func WriteDOT[T Number](w io.Writer, l *Leaf[T]) error {
+	var sb strings.Builder
+	sb.WriteString("digraph BST {\n")
+	var visit func(l *Leaf[T])
+	visit = func(l *Leaf[T]) {
+		fmt.Fprintf(&sb, "\t\"%v\";\n", l.Data)
+		if l.Left != nil {
+			fmt.Fprintf(&sb, "\t\"%v\" -> \"%v\" [label=\"L\"];\n", l.Data, l.Left.Data)
+			visit(l.Left)
+		}
+		if l.Right != nil {
+			fmt.Fprintf(&sb, "\t\"%v\" -> \"%v\" [label=\"R\"];\n", l.Data, l.Right.Data)
+			visit(l.Right)
+		}
+	}
+	if l != nil {
+		visit(l)
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
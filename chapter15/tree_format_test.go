@@ -0,0 +1,81 @@
+package chapter14
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinarySearchTreeJSON(t *testing.T) {
+	tree := NewBinarySearchTree([]int{50, 25, 75, 33})
+	data, err := json.Marshal(tree)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data":50,"left":{"data":25,"right":{"data":33}},"right":{"data":75}}`, string(data))
+
+	var decoded *Leaf[int]
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, Equal(tree, decoded))
+}
+
+func TestBinarySearchTreeEqual(t *testing.T) {
+	a := NewBinarySearchTree([]int{2, 1, 3})
+	b := NewBinarySearchTree([]int{2, 3, 1})
+	c := NewBinarySearchTree([]int{1, 2, 3})
+	assert.True(t, Equal(a, b))
+	assert.False(t, Equal(a, c))
+	assert.True(t, SameValues(a, c))
+	assert.False(t, SameValues(a, NewBinarySearchTree([]int{1, 2})))
+	assert.True(t, Equal[int](nil, nil))
+	assert.False(t, Equal(a, nil))
+}
+
+func TestBinarySearchTreePreOrder(t *testing.T) {
+	tree := NewBinarySearchTree([]float64{50, 25, 75, 10, 33, 4, 11, 30, 40, 56, 89, 52, 61, 82, 95.5})
+	var buf bytes.Buffer
+	require.NoError(t, WritePreOrder(&buf, tree))
+	assert.Equal(t, "50 25 10 4 11 33 30 40 75 56 52 61 89 82 95.5", buf.String())
+
+	decoded, err := ReadPreOrder[float64](&buf)
+	require.NoError(t, err)
+	assert.True(t, Equal(tree, decoded))
+
+	empty, err := ReadPreOrder[int](strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+
+	_, err = ReadPreOrder[int](strings.NewReader("50 25 60 30"))
+	assert.EqualError(t, err, "value 30 at 3 is not in binary search tree pre-order")
+	_, err = ReadPreOrder[int](strings.NewReader("50 x"))
+	assert.Error(t, err)
+}
+
+func TestBinarySearchTreeRender(t *testing.T) {
+	tree := NewBinarySearchTree([]int{50, 25, 75, 33, 10, 89})
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, tree))
+	assert.Equal(t, `50
+├── L: 25
+│   ├── L: 10
+│   └── R: 33
+└── R: 75
+    └── R: 89
+`, buf.String())
+}
+
+func TestBinarySearchTreeWriteDOT(t *testing.T) {
+	tree := NewBinarySearchTree([]int{50, 25, 75})
+	var buf bytes.Buffer
+	require.NoError(t, WriteDOT(&buf, tree))
+	assert.Equal(t, `digraph BST {
+	"50";
+	"50" -> "25" [label="L"];
+	"25";
+	"50" -> "75" [label="R"];
+	"75";
+}
+`, buf.String())
+}
@@ -0,0 +1,287 @@
+package chapter14
+
+// Ordered covers all types which can be compared using < and >.
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// Compare returns -1 if a is less than b, 1 if a is greater than b and 0 if they are equal. A floating
+// point NaN is less than every other value and equal to itself, so it still has a place in the order.
+func Compare[K Ordered](a, b K) int {
+	// NaN is the only value which isn't equal to itself.
+	aNaN, bNaN := a != a, b != b
+	switch {
+	case aNaN && bNaN:
+		return 0
+	case aNaN:
+		return -1
+	case bNaN:
+		return 1
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+type treeMapNode[K, V any] struct {
+	left  *treeMapNode[K, V]
+	right *treeMapNode[K, V]
+	key   K
+	value V
+	red   bool
+	// size is the number of nodes in this subtree. It's what makes Rank and Select logarithmic.
+	size int
+}
+
+// TreeMap is a map which keeps its keys in order. Unlike Leaf it can hold any key type and a value
+// for every key, and it stays balanced because it's built on the same left-leaning red-black tree
+// as RedBlackTree.
+type TreeMap[K, V any] struct {
+	root    *treeMapNode[K, V]
+	compare func(a, b K) int
+	size    int
+}
+
+// NewTreeMap creates a TreeMap for keys which can be compared with < and >.
+func NewTreeMap[K Ordered, V any]() *TreeMap[K, V] {
+	return NewTreeMapFunc[K, V](Compare[K])
+}
+
+// NewTreeMapFunc creates a TreeMap which orders its keys using compare. compare must return a negative
+// number if a comes before b, a positive one if a comes after b and 0 if they are the same key.
+func NewTreeMapFunc[K, V any](compare func(a, b K) int) *TreeMap[K, V] {
+	return &TreeMap[K, V]{
+		compare: compare,
+	}
+}
+
+func (n *treeMapNode[K, V]) isRed() bool {
+	return n != nil && n.red
+}
+
+func (n *treeMapNode[K, V]) len() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *treeMapNode[K, V]) updateSize() {
+	n.size = 1 + n.left.len() + n.right.len()
+}
+
+func (n *treeMapNode[K, V]) rotateLeft() *treeMapNode[K, V] {
+	right := n.right
+	n.right = right.left
+	right.left = n
+	right.red = n.red
+	n.red = true
+	right.size = n.size
+	n.updateSize()
+	return right
+}
+
+func (n *treeMapNode[K, V]) rotateRight() *treeMapNode[K, V] {
+	left := n.left
+	n.left = left.right
+	left.right = n
+	left.red = n.red
+	n.red = true
+	left.size = n.size
+	n.updateSize()
+	return left
+}
+
+func (n *treeMapNode[K, V]) flipColors() {
+	n.red = !n.red
+	n.left.red = !n.left.red
+	n.right.red = !n.right.red
+}
+
+func (n *treeMapNode[K, V]) fixUp() *treeMapNode[K, V] {
+	if n.right.isRed() && !n.left.isRed() {
+		n = n.rotateLeft()
+	}
+	if n.left.isRed() && n.left.left.isRed() {
+		n = n.rotateRight()
+	}
+	if n.left.isRed() && n.right.isRed() {
+		n.flipColors()
+	}
+	n.updateSize()
+	return n
+}
+
+func (n *treeMapNode[K, V]) moveRedLeft() *treeMapNode[K, V] {
+	n.flipColors()
+	if n.right.left.isRed() {
+		n.right = n.right.rotateRight()
+		n = n.rotateLeft()
+		n.flipColors()
+	}
+	return n
+}
+
+func (n *treeMapNode[K, V]) moveRedRight() *treeMapNode[K, V] {
+	n.flipColors()
+	if n.left.left.isRed() {
+		n = n.rotateRight()
+		n.flipColors()
+	}
+	return n
+}
+
+func (n *treeMapNode[K, V]) min() *treeMapNode[K, V] {
+	for n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func (n *treeMapNode[K, V]) max() *treeMapNode[K, V] {
+	for n.right != nil {
+		n = n.right
+	}
+	return n
+}
+
+func (m *TreeMap[K, V]) find(key K) *treeMapNode[K, V] {
+	node := m.root
+	for node != nil {
+		c := m.compare(key, node.key)
+		switch {
+		case c < 0:
+			node = node.left
+		case c > 0:
+			node = node.right
+		default:
+			return node
+		}
+	}
+	return nil
+}
+
+// Put sets the value for key. It returns true if the key was already there and its value got replaced.
+func (m *TreeMap[K, V]) Put(key K, value V) bool {
+	var replaced bool
+	m.root, replaced = m.put(m.root, key, value)
+	m.root.red = false
+	if !replaced {
+		m.size++
+	}
+	return replaced
+}
+
+func (m *TreeMap[K, V]) put(node *treeMapNode[K, V], key K, value V) (*treeMapNode[K, V], bool) {
+	if node == nil {
+		return &treeMapNode[K, V]{key: key, value: value, red: true, size: 1}, false
+	}
+
+	var replaced bool
+	c := m.compare(key, node.key)
+	switch {
+	case c < 0:
+		node.left, replaced = m.put(node.left, key, value)
+	case c > 0:
+		node.right, replaced = m.put(node.right, key, value)
+	default:
+		node.value = value
+		return node, true
+	}
+
+	return node.fixUp(), replaced
+}
+
+// Get returns the value stored for key.
+func (m *TreeMap[K, V]) Get(key K) (V, bool) {
+	node := m.find(key)
+	if node == nil {
+		var v V
+		return v, false
+	}
+	return node.value, true
+}
+
+// Delete removes key. It returns false if the key wasn't there.
+func (m *TreeMap[K, V]) Delete(key K) bool {
+	// The delete below relies on the key being in the tree.
+	if m.find(key) == nil {
+		return false
+	}
+
+	if !m.root.left.isRed() && !m.root.right.isRed() {
+		m.root.red = true
+	}
+	m.root = m.delete(m.root, key)
+	if m.root != nil {
+		m.root.red = false
+	}
+	m.size--
+	return true
+}
+
+func (m *TreeMap[K, V]) delete(node *treeMapNode[K, V], key K) *treeMapNode[K, V] {
+	if m.compare(key, node.key) < 0 {
+		if !node.left.isRed() && !node.left.left.isRed() {
+			node = node.moveRedLeft()
+		}
+		node.left = m.delete(node.left, key)
+		return node.fixUp()
+	}
+
+	if node.left.isRed() {
+		node = node.rotateRight()
+	}
+	if m.compare(key, node.key) == 0 && node.right == nil {
+		return nil
+	}
+	if !node.right.isRed() && !node.right.left.isRed() {
+		node = node.moveRedRight()
+	}
+	if m.compare(key, node.key) == 0 {
+		// Replace the entry with its successor and delete the successor instead.
+		successor := node.right.min()
+		node.key, node.value = successor.key, successor.value
+		node.right = deleteMinTreeMapNode(node.right)
+	} else {
+		node.right = m.delete(node.right, key)
+	}
+
+	return node.fixUp()
+}
+
+func deleteMinTreeMapNode[K, V any](node *treeMapNode[K, V]) *treeMapNode[K, V] {
+	if node.left == nil {
+		return nil
+	}
+	if !node.left.isRed() && !node.left.left.isRed() {
+		node = node.moveRedLeft()
+	}
+	node.left = deleteMinTreeMapNode(node.left)
+	return node.fixUp()
+}
+
+// Len returns the number of keys.
+func (m *TreeMap[K, V]) Len() int {
+	return m.size
+}
+
+// Min returns the smallest key and its value. It returns false if the map is empty.
+func (m *TreeMap[K, V]) Min() (K, V, bool) {
+	if m.root == nil {
+		return entryOf[K, V](nil)
+	}
+	return entryOf(m.root.min())
+}
+
+// Max returns the largest key and its value. It returns false if the map is empty.
+func (m *TreeMap[K, V]) Max() (K, V, bool) {
+	if m.root == nil {
+		return entryOf[K, V](nil)
+	}
+	return entryOf(m.root.max())
+}
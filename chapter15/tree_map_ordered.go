@@ -0,0 +1,186 @@
+package chapter14
+
+// Floor returns the largest key which is less than or equal to key, and its value.
+func (m *TreeMap[K, V]) Floor(key K) (K, V, bool) {
+	var found *treeMapNode[K, V]
+	node := m.root
+	for node != nil {
+		c := m.compare(key, node.key)
+		switch {
+		case c == 0:
+			return node.key, node.value, true
+		case c < 0:
+			node = node.left
+		default:
+			// This is a candidate, but there might be a larger one on the right.
+			found = node
+			node = node.right
+		}
+	}
+	return entryOf(found)
+}
+
+// Ceiling returns the smallest key which is greater than or equal to key, and its value.
+func (m *TreeMap[K, V]) Ceiling(key K) (K, V, bool) {
+	var found *treeMapNode[K, V]
+	node := m.root
+	for node != nil {
+		c := m.compare(key, node.key)
+		switch {
+		case c == 0:
+			return node.key, node.value, true
+		case c > 0:
+			node = node.right
+		default:
+			found = node
+			node = node.left
+		}
+	}
+	return entryOf(found)
+}
+
+func entryOf[K, V any](node *treeMapNode[K, V]) (K, V, bool) {
+	if node == nil {
+		var (
+			k K
+			v V
+		)
+		return k, v, false
+	}
+	return node.key, node.value, true
+}
+
+// Rank returns the number of keys which are less than key. The key doesn't have to be in the map.
+func (m *TreeMap[K, V]) Rank(key K) int {
+	rank := 0
+	node := m.root
+	for node != nil {
+		c := m.compare(key, node.key)
+		switch {
+		case c < 0:
+			node = node.left
+		case c > 0:
+			// Everything on the left and the node itself are smaller.
+			rank += node.left.len() + 1
+			node = node.right
+		default:
+			return rank + node.left.len()
+		}
+	}
+	return rank
+}
+
+// Select returns the key with the given rank, which is the k-th smallest key counting from 0.
+func (m *TreeMap[K, V]) Select(rank int) (K, V, bool) {
+	if rank < 0 || rank >= m.root.len() {
+		return entryOf[K, V](nil)
+	}
+
+	node := m.root
+	for {
+		left := node.left.len()
+		switch {
+		case rank < left:
+			node = node.left
+		case rank > left:
+			rank -= left + 1
+			node = node.right
+		default:
+			return node.key, node.value, true
+		}
+	}
+}
+
+// Range calls fn for every key between lo and hi inclusive in ascending order. It skips the
+// subtrees which are entirely out of range, so it only visits the keys it reports and the path to them.
+// Walking stops as soon as fn returns false.
+func (m *TreeMap[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	m.rangeNode(m.root, lo, hi, fn)
+}
+
+func (m *TreeMap[K, V]) rangeNode(node *treeMapNode[K, V], lo, hi K, fn func(key K, value V) bool) bool {
+	if node == nil {
+		return true
+	}
+
+	fromLo, toHi := m.compare(lo, node.key), m.compare(hi, node.key)
+	if fromLo < 0 && !m.rangeNode(node.left, lo, hi, fn) {
+		return false
+	}
+	if fromLo <= 0 && toHi >= 0 && !fn(node.key, node.value) {
+		return false
+	}
+	if toHi > 0 {
+		return m.rangeNode(node.right, lo, hi, fn)
+	}
+	return true
+}
+
+// InOrder calls fn for every entry in ascending order of keys. Walking stops as soon as fn returns false.
+func (m *TreeMap[K, V]) InOrder(fn func(key K, value V) bool) {
+	inOrder(m.root, fn)
+}
+
+func inOrder[K, V any](node *treeMapNode[K, V], fn func(key K, value V) bool) bool {
+	if node == nil {
+		return true
+	}
+	return inOrder(node.left, fn) && fn(node.key, node.value) && inOrder(node.right, fn)
+}
+
+// PreOrder calls fn for every node before its children. Walking stops as soon as fn returns false.
+func (m *TreeMap[K, V]) PreOrder(fn func(key K, value V) bool) {
+	preOrder(m.root, fn)
+}
+
+func preOrder[K, V any](node *treeMapNode[K, V], fn func(key K, value V) bool) bool {
+	if node == nil {
+		return true
+	}
+	return fn(node.key, node.value) && preOrder(node.left, fn) && preOrder(node.right, fn)
+}
+
+// PostOrder calls fn for every node after its children. Walking stops as soon as fn returns false.
+func (m *TreeMap[K, V]) PostOrder(fn func(key K, value V) bool) {
+	postOrder(m.root, fn)
+}
+
+func postOrder[K, V any](node *treeMapNode[K, V], fn func(key K, value V) bool) bool {
+	if node == nil {
+		return true
+	}
+	return postOrder(node.left, fn) && postOrder(node.right, fn) && fn(node.key, node.value)
+}
+
+// LevelOrder calls fn for every node level by level from the root down, left to right within a level.
+// Walking stops as soon as fn returns false.
+func (m *TreeMap[K, V]) LevelOrder(fn func(key K, value V) bool) {
+	if m.root == nil {
+		return
+	}
+
+	queue := []*treeMapNode[K, V]{m.root}
+	var current *treeMapNode[K, V]
+	for len(queue) > 0 {
+		current, queue = queue[0], queue[1:]
+		if !fn(current.key, current.value) {
+			return
+		}
+		if current.left != nil {
+			queue = append(queue, current.left)
+		}
+		if current.right != nil {
+			queue = append(queue, current.right)
+		}
+	}
+}
+
+// Keys returns all keys in ascending order.
+func (m *TreeMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.size)
+	m.InOrder(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
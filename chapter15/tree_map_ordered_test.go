@@ -0,0 +1,120 @@
+package chapter14
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newEvenTreeMap creates a map of the even numbers from 0 to 98 mapped to their halves.
+func newEvenTreeMap() *TreeMap[int, int] {
+	m := NewTreeMap[int, int]()
+	for _, v := range rand.New(rand.NewSource(1)).Perm(50) {
+		m.Put(v*2, v)
+	}
+	return m
+}
+
+func TestTreeMapFloorCeiling(t *testing.T) {
+	m := newEvenTreeMap()
+	k, v, ok := m.Floor(11)
+	assert.True(t, ok)
+	assert.Equal(t, 10, k)
+	assert.Equal(t, 5, v)
+	k, _, _ = m.Floor(12)
+	assert.Equal(t, 12, k)
+	_, _, ok = m.Floor(-1)
+	assert.False(t, ok)
+
+	k, _, ok = m.Ceiling(11)
+	assert.True(t, ok)
+	assert.Equal(t, 12, k)
+	k, _, _ = m.Ceiling(12)
+	assert.Equal(t, 12, k)
+	_, _, ok = m.Ceiling(99)
+	assert.False(t, ok)
+}
+
+func TestTreeMapRankSelect(t *testing.T) {
+	m := newEvenTreeMap()
+	assert.Equal(t, 0, m.Rank(-5))
+	assert.Equal(t, 0, m.Rank(0))
+	assert.Equal(t, 5, m.Rank(10))
+	assert.Equal(t, 6, m.Rank(11))
+	assert.Equal(t, 50, m.Rank(1000))
+
+	for i := 0; i < 50; i++ {
+		k, v, ok := m.Select(i)
+		assert.True(t, ok)
+		assert.Equal(t, i*2, k)
+		assert.Equal(t, i, v)
+		assert.Equal(t, i, m.Rank(k))
+	}
+	_, _, ok := m.Select(50)
+	assert.False(t, ok)
+	_, _, ok = m.Select(-1)
+	assert.False(t, ok)
+
+	// The subtree sizes have to survive deletes too.
+	for i := 0; i < 50; i += 2 {
+		m.Delete(i * 2)
+	}
+	k, _, _ := m.Select(0)
+	assert.Equal(t, 2, k)
+	assert.Equal(t, 5, m.Rank(21))
+	assert.Equal(t, 25, m.root.len())
+}
+
+func TestTreeMapRange(t *testing.T) {
+	m := newEvenTreeMap()
+	var keys []int
+	m.Range(9, 17, func(key, _ int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.Equal(t, []int{10, 12, 14, 16}, keys)
+
+	keys = nil
+	m.Range(10, 100, func(key, _ int) bool {
+		keys = append(keys, key)
+		return len(keys) < 3
+	})
+	assert.Equal(t, []int{10, 12, 14}, keys)
+
+	keys = nil
+	m.Range(11, 11, func(key, _ int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.Nil(t, keys)
+}
+
+func TestTreeMapTraversals(t *testing.T) {
+	m := NewTreeMap[int, string]()
+	for _, k := range []int{1, 2, 3, 4, 5, 6, 7} {
+		m.Put(k, "")
+	}
+	// Inserting 1 to 7 into a left-leaning red-black tree gives a perfectly balanced tree rooted at 4.
+	collect := func(walk func(func(int, string) bool)) []int {
+		var keys []int
+		walk(func(k int, _ string) bool {
+			keys = append(keys, k)
+			return true
+		})
+		return keys
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, collect(m.InOrder))
+	assert.Equal(t, []int{4, 2, 1, 3, 6, 5, 7}, collect(m.PreOrder))
+	assert.Equal(t, []int{1, 3, 2, 5, 7, 6, 4}, collect(m.PostOrder))
+	assert.Equal(t, []int{4, 2, 6, 1, 3, 5, 7}, collect(m.LevelOrder))
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, m.Keys())
+
+	var visited []int
+	m.LevelOrder(func(k int, _ string) bool {
+		visited = append(visited, k)
+		return len(visited) < 2
+	})
+	assert.Equal(t, []int{4, 2}, visited)
+	assert.Empty(t, collect(NewTreeMap[int, string]().LevelOrder))
+}
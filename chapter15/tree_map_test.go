@@ -0,0 +1,133 @@
+package chapter14
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeMapPutGet(t *testing.T) {
+	m := NewTreeMap[string, int]()
+	assert.False(t, m.Put("banana", 1))
+	assert.False(t, m.Put("apple", 2))
+	assert.False(t, m.Put("cherry", 3))
+	assert.True(t, m.Put("apple", 4))
+	assert.Equal(t, 3, m.Len())
+
+	v, ok := m.Get("apple")
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+	_, ok = m.Get("durian")
+	assert.False(t, ok)
+}
+
+func TestTreeMapMinMax(t *testing.T) {
+	m := NewTreeMap[int, string]()
+	_, _, ok := m.Min()
+	assert.False(t, ok)
+	_, _, ok = m.Max()
+	assert.False(t, ok)
+
+	for _, v := range rand.New(rand.NewSource(1)).Perm(100) {
+		m.Put(v, "value")
+	}
+	k, _, ok := m.Min()
+	assert.True(t, ok)
+	assert.Equal(t, 0, k)
+	k, _, ok = m.Max()
+	assert.True(t, ok)
+	assert.Equal(t, 99, k)
+}
+
+func TestTreeMapDelete(t *testing.T) {
+	m := NewTreeMap[int, int]()
+	assert.False(t, m.Delete(1))
+	r := rand.New(rand.NewSource(1))
+	for _, v := range r.Perm(100) {
+		m.Put(v, v*2)
+	}
+	for _, v := range r.Perm(50) {
+		assert.True(t, m.Delete(v))
+		assert.False(t, m.Delete(v))
+	}
+	assert.Equal(t, 50, m.Len())
+	k, v, _ := m.Min()
+	assert.Equal(t, 50, k)
+	assert.Equal(t, 100, v)
+	for i := 50; i < 100; i++ {
+		assert.True(t, m.Delete(i))
+	}
+	assert.Equal(t, 0, m.Len())
+	assert.Nil(t, m.root)
+}
+
+func TestTreeMapFunc(t *testing.T) {
+	m := NewTreeMapFunc[time.Time, string](func(a, b time.Time) int {
+		switch {
+		case a.Before(b):
+			return -1
+		case a.After(b):
+			return 1
+		}
+		return 0
+	})
+	now := time.Now()
+	m.Put(now, "now")
+	m.Put(now.Add(-time.Hour), "before")
+	m.Put(now.Add(time.Hour), "after")
+	_, v, _ := m.Min()
+	assert.Equal(t, "before", v)
+
+	// Case insensitive keys.
+	ci := NewTreeMapFunc[string, int](func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	ci.Put("Key", 1)
+	assert.True(t, ci.Put("KEY", 2))
+	v2, ok := ci.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v2)
+}
+
+func TestCompareNaN(t *testing.T) {
+	nan := math.NaN()
+	assert.Equal(t, 0, Compare(nan, nan))
+	assert.Equal(t, -1, Compare(nan, math.Inf(-1)))
+	assert.Equal(t, 1, Compare(0, nan))
+	assert.Equal(t, -1, Compare(1.5, 2))
+
+	type floatMap interface {
+		Put(key float64, value string) bool
+		Get(key float64) (string, bool)
+		Len() int
+		Min() (float64, string, bool)
+	}
+	for name, m := range map[string]floatMap{
+		"TreeMap":  NewTreeMap[float64, string](),
+		"Treap":    NewTreap[float64, string](1),
+		"SkipList": NewSkipList[float64, string](1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			m.Put(1, "one")
+			m.Put(2, "two")
+			// A NaN key gets its own place at the front instead of overwriting the key it lands on.
+			assert.False(t, m.Put(nan, "nan"))
+			assert.True(t, m.Put(nan, "still nan"))
+			assert.Equal(t, 3, m.Len())
+
+			v, ok := m.Get(1)
+			assert.True(t, ok)
+			assert.Equal(t, "one", v)
+			v, ok = m.Get(nan)
+			assert.True(t, ok)
+			assert.Equal(t, "still nan", v)
+			k, _, ok := m.Min()
+			assert.True(t, ok)
+			assert.True(t, math.IsNaN(k))
+		})
+	}
+}
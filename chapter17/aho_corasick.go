@@ -0,0 +1,171 @@
+package chapter17
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+type ahoCorasickNode struct {
+	children map[byte]*ahoCorasickNode
+	// fail points to the node of the longest proper suffix of this node's path which is also in the trie.
+	// It's where matching continues when the next byte has no child.
+	fail *ahoCorasickNode
+	// output points to the closest node down the fail chain where a pattern ends. Following these
+	// reports every shorter pattern which ends at the same position.
+	output *ahoCorasickNode
+	// pattern is the index of the pattern ending at this node or -1 if there is none.
+	pattern int
+}
+
+// AhoCorasick finds all occurrences of many patterns in a single pass over the text. It's a byte-wise
+// trie of the patterns where every node also knows where to continue when the text stops matching.
+type AhoCorasick struct {
+	root     *ahoCorasickNode
+	patterns []string
+}
+
+// PatternMatch is a single occurrence of a pattern in the text.
+type PatternMatch struct {
+	Pattern string
+	// Offset is the byte offset of the first byte of the match.
+	Offset int
+}
+
+func newAhoCorasickNode() *ahoCorasickNode {
+	return &ahoCorasickNode{
+		children: make(map[byte]*ahoCorasickNode),
+		pattern:  -1,
+	}
+}
+
+// NewAhoCorasick compiles the automaton from a list of patterns. Empty and duplicate patterns are ignored.
+func NewAhoCorasick(patterns []string) *AhoCorasick {
+	a := &AhoCorasick{
+		root: newAhoCorasickNode(),
+	}
+
+	for _, p := range patterns {
+		if p == "" {
+			continue
+		}
+
+		node := a.root
+		for i := 0; i < len(p); i++ {
+			next, ok := node.children[p[i]]
+			if !ok {
+				next = newAhoCorasickNode()
+				node.children[p[i]] = next
+			}
+			node = next
+		}
+
+		if node.pattern == -1 {
+			node.pattern = len(a.patterns)
+			a.patterns = append(a.patterns, p)
+		}
+	}
+
+	a.link()
+	return a
+}
+
+// NewAhoCorasickFromTrie compiles the automaton from every word in the trie.
+func NewAhoCorasickFromTrie(t *Trie) *AhoCorasick {
+	return NewAhoCorasick(t.CollectAllWords("", nil, []string{}))
+}
+
+// link sets up the fail and output links. It goes breadth first, because the fail link of a node
+// is always shallower than the node itself.
+func (a *AhoCorasick) link() {
+	queue := make([]*ahoCorasickNode, 0)
+	for _, child := range a.root.children {
+		child.fail = a.root
+		queue = append(queue, child)
+	}
+
+	var current *ahoCorasickNode
+	for len(queue) > 0 {
+		current, queue = queue[0], queue[1:]
+		for b, child := range current.children {
+			child.fail = current.fail.next(b, a.root)
+			if child.fail.pattern != -1 {
+				child.output = child.fail
+			} else {
+				child.output = child.fail.output
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// next returns the state after reading b, following fail links until there is a way to go on.
+func (n *ahoCorasickNode) next(b byte, root *ahoCorasickNode) *ahoCorasickNode {
+	for n != root {
+		if child, ok := n.children[b]; ok {
+			return child
+		}
+		n = n.fail
+	}
+	if child, ok := root.children[b]; ok {
+		return child
+	}
+	return root
+}
+
+// step reads a single byte at offset and reports every pattern which ends with it.
+// It returns the new state and false if fn asked to stop.
+func (a *AhoCorasick) step(state *ahoCorasickNode, b byte, offset int, fn func(PatternMatch) bool) (*ahoCorasickNode, bool) {
+	state = state.next(b, a.root)
+
+	match := state
+	if match.pattern == -1 {
+		match = match.output
+	}
+	for ; match != nil; match = match.output {
+		p := a.patterns[match.pattern]
+		if !fn(PatternMatch{Pattern: p, Offset: offset - len(p) + 1}) {
+			return state, false
+		}
+	}
+
+	return state, true
+}
+
+// FindAll returns every match in text, ordered by where the match ends. Matches ending at the same
+// place are ordered from the longest to the shortest.
+func (a *AhoCorasick) FindAll(text string) []PatternMatch {
+	var matches []PatternMatch
+	collect := func(m PatternMatch) bool {
+		matches = append(matches, m)
+		return true
+	}
+
+	state := a.root
+	for i := 0; i < len(text); i++ {
+		state, _ = a.step(state, text[i], i, collect)
+	}
+
+	return matches
+}
+
+// Scan reads r until EOF and calls fn with every match in the same order as FindAll.
+// Scanning stops early if fn returns false.
+func (a *AhoCorasick) Scan(r io.Reader, fn func(PatternMatch) bool) error {
+	reader := bufio.NewReader(r)
+	state := a.root
+	for offset := 0; ; offset++ {
+		b, err := reader.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var ok bool
+		if state, ok = a.step(state, b, offset, fn); !ok {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package chapter17
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAhoCorasickFindAll(t *testing.T) {
+	a := NewAhoCorasick([]string{"he", "she", "his", "hers", "she", ""})
+	result := a.FindAll("ushers")
+	assert.Equal(t, []PatternMatch{
+		{Pattern: "she", Offset: 1},
+		{Pattern: "he", Offset: 2},
+		{Pattern: "hers", Offset: 2},
+	}, result)
+	assert.Nil(t, a.FindAll("nothing to see"))
+}
+
+func TestAhoCorasickOverlapping(t *testing.T) {
+	a := NewAhoCorasick([]string{"a", "aa", "aaa"})
+	result := a.FindAll("aaa")
+	assert.Equal(t, []PatternMatch{
+		{Pattern: "a", Offset: 0},
+		{Pattern: "aa", Offset: 0},
+		{Pattern: "a", Offset: 1},
+		{Pattern: "aaa", Offset: 0},
+		{Pattern: "aa", Offset: 1},
+		{Pattern: "a", Offset: 2},
+	}, result)
+}
+
+func TestAhoCorasickFromTrie(t *testing.T) {
+	trie := NewTrie()
+	trie.Insert("error")
+	trie.Insert("héllo")
+	a := NewAhoCorasickFromTrie(trie)
+	result := a.FindAll("héllo, an error")
+	assert.Equal(t, []PatternMatch{
+		{Pattern: "héllo", Offset: 0},
+		{Pattern: "error", Offset: 11},
+	}, result)
+}
+
+func TestAhoCorasickScan(t *testing.T) {
+	a := NewAhoCorasick([]string{"he", "she", "his", "hers"})
+	var result []PatternMatch
+	err := a.Scan(strings.NewReader("ushers"), func(m PatternMatch) bool {
+		result = append(result, m)
+		return true
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, a.FindAll("ushers"), result)
+
+	// Stop after the first match.
+	result = nil
+	err = a.Scan(strings.NewReader("ushers"), func(m PatternMatch) bool {
+		result = append(result, m)
+		return false
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []PatternMatch{{Pattern: "she", Offset: 1}}, result)
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("boom")
+}
+
+func TestAhoCorasickScanError(t *testing.T) {
+	a := NewAhoCorasick([]string{"he"})
+	err := a.Scan(failingReader{}, func(PatternMatch) bool { return true })
+	assert.EqualError(t, err, "boom")
+}
@@ -0,0 +1,145 @@
+package chapter17
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrentTrie is a Trie which can be read and written from multiple goroutines at the same time.
+// Nodes are never changed once they are published. A write copies the nodes on the path of the
+// word, which is called path copying, and then swaps in the new root. Readers never wait for
+// writers and always see the trie either before or after a whole write, never in between.
+type ConcurrentTrie struct {
+	// mu only serializes the writers, readers don't need it.
+	mu   sync.Mutex
+	root atomic.Pointer[TrieNode]
+}
+
+func NewConcurrentTrie() *ConcurrentTrie {
+	c := &ConcurrentTrie{}
+	c.root.Store(&TrieNode{Children: make(map[rune]*TrieNode)})
+	return c
+}
+
+// TrieSnapshot is a read-only view of a ConcurrentTrie at one point in time. Its nodes are shared with
+// the ConcurrentTrie and with other snapshots, so it only offers queries and never hands out nodes.
+type TrieSnapshot struct {
+	trie Trie
+}
+
+// Snapshot returns the current state of the trie, which is unaffected by later writes.
+func (c *ConcurrentTrie) Snapshot() *TrieSnapshot {
+	return &TrieSnapshot{trie: Trie{Root: c.root.Load()}}
+}
+
+// Contains returns true if the exact word is in the snapshot.
+func (s *TrieSnapshot) Contains(word string) bool {
+	return s.trie.Contains(word)
+}
+
+// AutoComplete works like Trie.AutoComplete.
+func (s *TrieSnapshot) AutoComplete(prefix string) []string {
+	return s.trie.AutoComplete(prefix)
+}
+
+// AutoCorrect works like Trie.AutoCorrect.
+func (s *TrieSnapshot) AutoCorrect(prefix string) string {
+	return s.trie.AutoCorrect(prefix)
+}
+
+// Fuzzy works like Trie.Fuzzy.
+func (s *TrieSnapshot) Fuzzy(word string, maxDistance int) []FuzzyMatch {
+	return s.trie.Fuzzy(word, maxDistance)
+}
+
+// Match works like Trie.Match.
+func (s *TrieSnapshot) Match(pattern string) ([]string, error) {
+	return s.trie.Match(pattern)
+}
+
+// Words returns every word in the snapshot in alphabetical order.
+func (s *TrieSnapshot) Words() []string {
+	words := s.trie.CollectAllWords("", nil, []string{})
+	sort.Strings(words)
+	return words
+}
+
+func cloneNode(n *TrieNode) *TrieNode {
+	clone := &TrieNode{
+		Children:  make(map[rune]*TrieNode, len(n.Children)),
+		Frequency: n.Frequency,
+	}
+	for k, v := range n.Children {
+		clone.Children[k] = v
+	}
+	return clone
+}
+
+// Insert adds a word.
+func (c *ConcurrentTrie) Insert(word string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	root := cloneNode(c.root.Load())
+	currentNode := root
+	for _, r := range word {
+		var next *TrieNode
+		if v, ok := currentNode.Children[r]; ok {
+			next = cloneNode(v)
+		} else {
+			next = &TrieNode{Children: make(map[rune]*TrieNode)}
+		}
+		currentNode.Children[r] = next
+		currentNode = next
+	}
+
+	currentNode.Children['*'] = nil
+	currentNode.Frequency++
+	c.root.Store(root)
+}
+
+// Delete removes a word and prunes the nodes which no longer lead to any word.
+// It returns false if the word wasn't in the trie.
+func (c *ConcurrentTrie) Delete(word string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Check on the current version first, so nothing is copied for nothing.
+	if !c.Snapshot().Contains(word) {
+		return false
+	}
+
+	root := cloneNode(c.root.Load())
+	path := []*TrieNode{root}
+	runes := []rune(word)
+	for _, r := range runes {
+		next := cloneNode(path[len(path)-1].Children[r])
+		path[len(path)-1].Children[r] = next
+		path = append(path, next)
+	}
+
+	last := path[len(path)-1]
+	delete(last.Children, '*')
+	last.Frequency = 0
+
+	for i := len(path) - 1; i > 0; i-- {
+		if len(path[i].Children) > 0 {
+			break
+		}
+		delete(path[i-1].Children, runes[i-1])
+	}
+
+	c.root.Store(root)
+	return true
+}
+
+// Contains returns true if the exact word is in the trie.
+func (c *ConcurrentTrie) Contains(word string) bool {
+	return c.Snapshot().Contains(word)
+}
+
+// AutoComplete works like Trie.AutoComplete on the current snapshot.
+func (c *ConcurrentTrie) AutoComplete(prefix string) []string {
+	return c.Snapshot().AutoComplete(prefix)
+}
@@ -0,0 +1,75 @@
+package chapter17
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentTrieInsertDelete(t *testing.T) {
+	trie := NewConcurrentTrie()
+	trie.Insert("bat")
+	trie.Insert("batter")
+	trie.Insert("cat")
+	result := trie.AutoComplete("ba")
+	sort.Strings(result)
+	assert.Equal(t, []string{"t", "tter"}, result)
+
+	assert.False(t, trie.Delete("batt"))
+	assert.True(t, trie.Delete("batter"))
+	assert.False(t, trie.Contains("batter"))
+	assert.True(t, trie.Contains("bat"))
+	assert.Equal(t, []string{"bat", "cat"}, trie.Snapshot().Words())
+	// Deleting batter pruned everything after bat.
+	assert.Empty(t, trie.Snapshot().AutoComplete("batt"))
+}
+
+func TestConcurrentTrieSnapshot(t *testing.T) {
+	trie := NewConcurrentTrie()
+	trie.Insert("bat")
+	snapshot := trie.Snapshot()
+
+	trie.Insert("batter")
+	trie.Insert("bad")
+	trie.Delete("bat")
+
+	assert.Equal(t, []string{"bat"}, snapshot.Words())
+	assert.True(t, snapshot.Contains("bat"))
+	assert.Equal(t, []string{"bad", "batter"}, trie.Snapshot().Words())
+
+	matches, err := snapshot.Match("ba?")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bat"}, matches)
+	assert.Equal(t, "bat", snapshot.AutoCorrect("bet"))
+	assert.Len(t, snapshot.Fuzzy("bad", 1), 1)
+}
+
+func TestConcurrentTrieParallel(t *testing.T) {
+	trie := NewConcurrentTrie()
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				trie.Insert(fmt.Sprintf("word-%d-%d", w, i))
+			}
+		}(w)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				// A writer inserts its words in order, so if any of them is visible, the first one is too.
+				result := trie.AutoComplete(fmt.Sprintf("word-%d-", w))
+				if len(result) > 0 {
+					assert.True(t, trie.Contains(fmt.Sprintf("word-%d-0", w)))
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	assert.Len(t, trie.AutoComplete("word-"), 400)
+}
@@ -0,0 +1,236 @@
+package chapter17
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type dawgEdge struct {
+	char rune
+	node *dawgNode
+}
+
+type dawgNode struct {
+	id    int
+	final bool
+	// edges are ordered by char, which comes for free because words are added in order.
+	edges []dawgEdge
+}
+
+func (n *dawgNode) next(c rune) *dawgNode {
+	i := sort.Search(len(n.edges), func(i int) bool {
+		return n.edges[i].char >= c
+	})
+	if i < len(n.edges) && n.edges[i].char == c {
+		return n.edges[i].node
+	}
+	return nil
+}
+
+// signature identifies the node by everything reachable from it. Two nodes with the same signature
+// accept exactly the same suffixes, so one of them can be dropped.
+func (n *dawgNode) signature() string {
+	var sb strings.Builder
+	if n.final {
+		sb.WriteByte('!')
+	}
+	for _, e := range n.edges {
+		sb.WriteRune(e.char)
+		sb.WriteString(strconv.Itoa(e.node.id))
+		sb.WriteByte(';')
+	}
+	return sb.String()
+}
+
+// DAWG (directed acyclic word graph) is a minimal automaton accepting a set of words. It's a trie in
+// which identical suffixes are shared as well as prefixes. It can't be changed once it's built.
+type DAWG struct {
+	root *dawgNode
+}
+
+// NodeStats describes the size of a word graph for comparing implementations.
+type NodeStats struct {
+	Nodes int
+	Edges int
+}
+
+type uncheckedEdge struct {
+	parent *dawgNode
+	char   rune
+	child  *dawgNode
+}
+
+// DAWGBuilder builds a DAWG incrementally from words added in ascending order. Whenever a word
+// arrives, the part of the previous word it doesn't share can never change again, so those nodes
+// are minimized right away. This keeps the memory needed for building close to the size of the result.
+type DAWGBuilder struct {
+	root      *dawgNode
+	previous  string
+	unchecked []uncheckedEdge
+	minimized map[string]*dawgNode
+	nextID    int
+}
+
+func NewDAWGBuilder() *DAWGBuilder {
+	b := &DAWGBuilder{
+		minimized: make(map[string]*dawgNode),
+	}
+	b.root = b.newNode()
+	return b
+}
+
+func (b *DAWGBuilder) newNode() *dawgNode {
+	b.nextID++
+	return &dawgNode{id: b.nextID}
+}
+
+// Add adds a word. Words must be added in ascending order, adding the same word again does nothing.
+func (b *DAWGBuilder) Add(word string) error {
+	if word < b.previous {
+		return fmt.Errorf("words must be added in order: %q came after %q", word, b.previous)
+	}
+
+	previous, current := []rune(b.previous), []rune(word)
+	common := 0
+	for common < len(previous) && common < len(current) && previous[common] == current[common] {
+		common++
+	}
+
+	b.minimize(common)
+
+	node := b.root
+	if len(b.unchecked) > 0 {
+		node = b.unchecked[len(b.unchecked)-1].child
+	}
+
+	for _, c := range current[common:] {
+		next := b.newNode()
+		node.edges = append(node.edges, dawgEdge{char: c, node: next})
+		b.unchecked = append(b.unchecked, uncheckedEdge{parent: node, char: c, child: next})
+		node = next
+	}
+
+	node.final = true
+	b.previous = word
+	return nil
+}
+
+// minimize replaces the unchecked nodes deeper than downTo by an equivalent node if there is one.
+func (b *DAWGBuilder) minimize(downTo int) {
+	for i := len(b.unchecked) - 1; i >= downTo; i-- {
+		e := b.unchecked[i]
+		signature := e.child.signature()
+		if existing, ok := b.minimized[signature]; ok {
+			// The child is always the last edge added to its parent.
+			e.parent.edges[len(e.parent.edges)-1].node = existing
+		} else {
+			b.minimized[signature] = e.child
+		}
+	}
+	b.unchecked = b.unchecked[:downTo]
+}
+
+// Finish minimizes what's left and returns the DAWG. The builder must not be used afterwards.
+func (b *DAWGBuilder) Finish() *DAWG {
+	b.minimize(0)
+	b.minimized = nil
+	return &DAWG{root: b.root}
+}
+
+// BuildDAWG builds a DAWG from a list of words, which doesn't have to be sorted.
+func BuildDAWG(words []string) *DAWG {
+	sorted := append([]string{}, words...)
+	sort.Strings(sorted)
+
+	b := NewDAWGBuilder()
+	for _, w := range sorted {
+		// Can't fail, the words are sorted.
+		_ = b.Add(w)
+	}
+	return b.Finish()
+}
+
+func (d *DAWG) find(word string) *dawgNode {
+	node := d.root
+	for _, c := range word {
+		node = node.next(c)
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// Search returns true if any word starts with prefix, which is when Trie.Search returns a node. The
+// nodes of a DAWG are shared between words, so unlike the Trie it doesn't hand them out.
+func (d *DAWG) Search(prefix string) bool {
+	return d.find(prefix) != nil
+}
+
+// Contains returns true if word is one of the words the DAWG was built from.
+func (d *DAWG) Contains(word string) bool {
+	node := d.find(word)
+	return node != nil && node.final
+}
+
+// AutoComplete returns the rest of every word starting with prefix in alphabetical order. Just like
+// the Trie, the calling code must add the prefix back if it wants to display full words.
+func (d *DAWG) AutoComplete(prefix string) []string {
+	node := d.find(prefix)
+	if node == nil {
+		return nil
+	}
+	return node.collect(nil, []string{})
+}
+
+func (n *dawgNode) collect(word []rune, words []string) []string {
+	if n.final {
+		words = append(words, string(word))
+	}
+	for _, e := range n.edges {
+		words = e.node.collect(append(word, e.char), words)
+	}
+	return words
+}
+
+// Stats returns the number of distinct nodes and edges in the DAWG.
+func (d *DAWG) Stats() NodeStats {
+	var stats NodeStats
+	visited := make(map[*dawgNode]struct{})
+	var visit func(n *dawgNode)
+	visit = func(n *dawgNode) {
+		if _, ok := visited[n]; ok {
+			return
+		}
+		visited[n] = struct{}{}
+		stats.Nodes++
+		stats.Edges += len(n.edges)
+		for _, e := range n.edges {
+			visit(e.node)
+		}
+	}
+	visit(d.root)
+
+	return stats
+}
+
+// Stats returns the number of nodes and edges in the trie. The end of word markers are not counted.
+func (t *Trie) Stats() NodeStats {
+	var stats NodeStats
+	var visit func(n *TrieNode)
+	visit = func(n *TrieNode) {
+		stats.Nodes++
+		for k, v := range n.Children {
+			if k == '*' {
+				continue
+			}
+			stats.Edges++
+			visit(v)
+		}
+	}
+	visit(t.Root)
+
+	return stats
+}
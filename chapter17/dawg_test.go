@@ -0,0 +1,81 @@
+package chapter17
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDAWGContains(t *testing.T) {
+	d := BuildDAWG([]string{"tops", "taps", "top", "tap", "tap"})
+	assert.True(t, d.Contains("tap"))
+	assert.True(t, d.Contains("taps"))
+	assert.True(t, d.Contains("top"))
+	assert.True(t, d.Contains("tops"))
+	assert.False(t, d.Contains("to"))
+	assert.False(t, d.Contains("tips"))
+	assert.False(t, d.Contains(""))
+}
+
+func TestDAWGAutoComplete(t *testing.T) {
+	d := BuildDAWG([]string{"ace", "bad", "cat", "bat", "batter"})
+	assert.Equal(t, []string{"d", "t", "tter"}, d.AutoComplete("ba"))
+	assert.Equal(t, []string{"", "ter"}, d.AutoComplete("bat"))
+	assert.Nil(t, d.AutoComplete("x"))
+}
+
+func TestDAWGStats(t *testing.T) {
+	words := []string{"tap", "taps", "top", "tops"}
+	d := BuildDAWG(words)
+	// t -> {a, o} -> p -> s, where both middle letters lead to the same node.
+	assert.Equal(t, NodeStats{Nodes: 5, Edges: 5}, d.Stats())
+
+	trie := NewTrie()
+	for _, w := range words {
+		trie.Insert(w)
+	}
+	assert.Equal(t, NodeStats{Nodes: 8, Edges: 7}, trie.Stats())
+}
+
+func TestDAWGBuilderOutOfOrder(t *testing.T) {
+	b := NewDAWGBuilder()
+	assert.NoError(t, b.Add("b"))
+	assert.EqualError(t, b.Add("a"), `words must be added in order: "a" came after "b"`)
+}
+
+func TestDAWGMatchesTrie(t *testing.T) {
+	words := dictionaryWords(1000)
+	d := BuildDAWG(words)
+	trie := NewTrie()
+	for _, w := range words {
+		trie.Insert(w)
+		assert.True(t, d.Contains(w))
+	}
+	assert.Len(t, d.AutoComplete("https://example.com/section-4"), len(trie.AutoComplete("https://example.com/section-4")))
+	assert.Less(t, d.Stats().Nodes, trie.Stats().Nodes)
+}
+
+func TestDAWGAnswersLikeTrie(t *testing.T) {
+	words := []string{"tap", "taps", "top", "tops", "stop", "stops", "tar", "star", "car", "cars", "cart"}
+	d := BuildDAWG(words)
+	trie := NewTrie()
+	for _, w := range words {
+		trie.Insert(w)
+	}
+
+	queries := []string{"", "t", "ta", "tap", "taps", "tapss", "to", "s", "st", "sto", "c", "ca", "cart", "x", "tx", "star"}
+	for _, q := range queries {
+		assert.Equal(t, trie.Search(q) != nil, d.Search(q), "Search(%q)", q)
+		assert.Equal(t, trie.Contains(q), d.Contains(q), "Contains(%q)", q)
+
+		want := trie.AutoComplete(q)
+		sort.Strings(want)
+		got := d.AutoComplete(q)
+		if want == nil {
+			assert.Nil(t, got, "AutoComplete(%q)", q)
+		} else {
+			assert.Equal(t, want, got, "AutoComplete(%q)", q)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package chapter17
+
+import "sort"
+
+// FuzzyMatch is a word found by a fuzzy search together with its edit distance from the searched word.
+type FuzzyMatch struct {
+	Word      string
+	Distance  int
+	Frequency int
+}
+
+// Fuzzy returns all words which are at most maxDistance Levenshtein edits away from word.
+// The matches are sorted by distance, then by frequency in descending order and lastly alphabetically.
+func (t *Trie) Fuzzy(word string, maxDistance int) []FuzzyMatch {
+	return t.fuzzy(word, maxDistance, false)
+}
+
+// FuzzyDamerau works like Fuzzy, but it also counts swapping two adjacent characters as a single edit.
+// This is the optimal string alignment variant of the Damerau-Levenshtein distance.
+func (t *Trie) FuzzyDamerau(word string, maxDistance int) []FuzzyMatch {
+	return t.fuzzy(word, maxDistance, true)
+}
+
+func (t *Trie) fuzzy(word string, maxDistance int, transpositions bool) []FuzzyMatch {
+	if maxDistance < 0 {
+		return nil
+	}
+
+	target := []rune(word)
+
+	// The first row of the DP table is the distance from the empty string to every prefix of the word.
+	row := make([]int, len(target)+1)
+	for i := range row {
+		row[i] = i
+	}
+
+	s := &fuzzySearch{
+		target:         target,
+		maxDistance:    maxDistance,
+		transpositions: transpositions,
+	}
+
+	if t.Root.isWord() && row[len(target)] <= maxDistance {
+		s.matches = append(s.matches, FuzzyMatch{Distance: row[len(target)], Frequency: t.Root.Frequency})
+	}
+
+	for c, child := range t.Root.Children {
+		if c == '*' {
+			continue
+		}
+		s.walk(child, []rune{c}, nil, row)
+	}
+
+	sort.Slice(s.matches, func(i, j int) bool {
+		a, b := s.matches[i], s.matches[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if a.Frequency != b.Frequency {
+			return a.Frequency > b.Frequency
+		}
+		return a.Word < b.Word
+	})
+
+	return s.matches
+}
+
+type fuzzySearch struct {
+	target         []rune
+	maxDistance    int
+	transpositions bool
+	matches        []FuzzyMatch
+}
+
+// walk computes the DP row for the last character of word using the row of its parent. The row before
+// that is only needed to account for transpositions.
+func (s *fuzzySearch) walk(node *TrieNode, word []rune, previousPreviousRow, previousRow []int) {
+	c := word[len(word)-1]
+	row := make([]int, len(s.target)+1)
+	row[0] = previousRow[0] + 1
+	smallest := row[0]
+
+	for i := 1; i < len(row); i++ {
+		cost := 1
+		if s.target[i-1] == c {
+			cost = 0
+		}
+
+		row[i] = minOf(row[i-1]+1, previousRow[i]+1, previousRow[i-1]+cost)
+
+		if s.transpositions && previousPreviousRow != nil && i > 1 &&
+			s.target[i-1] == word[len(word)-2] && s.target[i-2] == c {
+			row[i] = minOf(row[i], previousPreviousRow[i-2]+1)
+		}
+
+		if row[i] < smallest {
+			smallest = row[i]
+		}
+	}
+
+	if node.isWord() && row[len(s.target)] <= s.maxDistance {
+		s.matches = append(s.matches, FuzzyMatch{
+			Word:      string(word),
+			Distance:  row[len(s.target)],
+			Frequency: node.Frequency,
+		})
+	}
+
+	// Nothing below this node can get any closer, so there is no point in going further.
+	if smallest > s.maxDistance {
+		return
+	}
+
+	for k, v := range node.Children {
+		if k == '*' {
+			continue
+		}
+		s.walk(v, append(word, k), previousRow, row)
+	}
+}
+
+// isWord returns true if a word ends at this node.
+func (n *TrieNode) isWord() bool {
+	_, ok := n.Children['*']
+	return ok
+}
+
+func minOf(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,54 @@
+package chapter17
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrieFuzzy(t *testing.T) {
+	trie := NewTrie()
+	trie.Insert("cat")
+	trie.Insert("cart")
+	trie.Insert("cut")
+	trie.Insert("cut")
+	trie.Insert("dog")
+	trie.Insert("catnip")
+	result := trie.Fuzzy("cat", 1)
+	assert.Equal(t, []FuzzyMatch{
+		{Word: "cat", Distance: 0, Frequency: 1},
+		{Word: "cut", Distance: 1, Frequency: 2},
+		{Word: "cart", Distance: 1, Frequency: 1},
+	}, result)
+	result = trie.Fuzzy("xyz", 1)
+	assert.Empty(t, result)
+}
+
+func TestTrieFuzzyDamerau(t *testing.T) {
+	trie := NewTrie()
+	trie.Insert("cat")
+	trie.Insert("act")
+	result := trie.Fuzzy("cta", 1)
+	assert.Empty(t, result)
+	result = trie.FuzzyDamerau("cta", 1)
+	assert.Equal(t, []FuzzyMatch{
+		{Word: "cat", Distance: 1, Frequency: 1},
+	}, result)
+	result = trie.FuzzyDamerau("cta", 2)
+	assert.Equal(t, []FuzzyMatch{
+		{Word: "cat", Distance: 1, Frequency: 1},
+		{Word: "act", Distance: 2, Frequency: 1},
+	}, result)
+}
+
+func TestTrieFuzzyEmptyWord(t *testing.T) {
+	trie := NewTrie()
+	trie.Insert("")
+	trie.Insert("a")
+	trie.Insert("ab")
+	result := trie.Fuzzy("", 1)
+	assert.Equal(t, []FuzzyMatch{
+		{Word: "", Distance: 0, Frequency: 1},
+		{Word: "a", Distance: 1, Frequency: 1},
+	}, result)
+}
@@ -0,0 +1,172 @@
+package chapter17
+
+import (
+	"fmt"
+	"sort"
+)
+
+type tokenKind int
+
+const (
+	literalToken tokenKind = iota
+	// anyCharToken is `?`, which matches exactly one character.
+	anyCharToken
+	// anyRunToken is `*`, which matches any number of characters including none.
+	anyRunToken
+	// classToken is `[...]`, which matches a single character from a set like `[abc]` or `[a-z]`.
+	// A leading `!` negates the set.
+	classToken
+)
+
+type charRange struct {
+	from, to rune
+}
+
+type patternToken struct {
+	kind    tokenKind
+	char    rune
+	ranges  []charRange
+	negated bool
+}
+
+func (p patternToken) matches(c rune) bool {
+	switch p.kind {
+	case literalToken:
+		return p.char == c
+	case classToken:
+		for _, r := range p.ranges {
+			if r.from <= c && c <= r.to {
+				return !p.negated
+			}
+		}
+		return p.negated
+	}
+	return true
+}
+
+// Match returns all words in the trie matching a pattern, sorted alphabetically. A pattern may contain
+// `?` for any single character, `*` for any run of characters and `[...]` classes such as `[aeiou]`,
+// `[a-f]` or `[!xyz]`. Any of these characters can be used literally by escaping it with a backslash.
+func (t *Trie) Match(pattern string) ([]string, error) {
+	tokens, err := parsePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	// Multiple `*` tokens can reach the same word in more than one way, so collect them in a set.
+	found := make(map[string]struct{})
+	t.match(t.Root, tokens, nil, found)
+
+	words := make([]string, 0, len(found))
+	for w := range found {
+		words = append(words, w)
+	}
+	sort.Strings(words)
+
+	return words, nil
+}
+
+func (t *Trie) match(node *TrieNode, tokens []patternToken, word []rune, found map[string]struct{}) {
+	if len(tokens) == 0 {
+		if node.isWord() {
+			found[string(word)] = struct{}{}
+		}
+		return
+	}
+
+	token := tokens[0]
+	switch token.kind {
+	case literalToken:
+		// The '*' child only marks the end of a word, so an escaped star can never match a character.
+		if v, ok := node.Children[token.char]; ok && token.char != '*' {
+			t.match(v, tokens[1:], append(word, token.char), found)
+		}
+	case anyRunToken:
+		// Either the run ends here, or it swallows one more character and goes on.
+		t.match(node, tokens[1:], word, found)
+		for k, v := range node.Children {
+			if k == '*' {
+				continue
+			}
+			t.match(v, tokens, append(word, k), found)
+		}
+	default:
+		for k, v := range node.Children {
+			if k == '*' || !token.matches(k) {
+				continue
+			}
+			t.match(v, tokens[1:], append(word, k), found)
+		}
+	}
+}
+
+func parsePattern(pattern string) ([]patternToken, error) {
+	runes := []rune(pattern)
+	var tokens []patternToken
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '?':
+			tokens = append(tokens, patternToken{kind: anyCharToken})
+		case '*':
+			// Consecutive stars are the same as a single one.
+			if len(tokens) > 0 && tokens[len(tokens)-1].kind == anyRunToken {
+				continue
+			}
+			tokens = append(tokens, patternToken{kind: anyRunToken})
+		case '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("pattern %q ends with an unfinished escape", pattern)
+			}
+			i++
+			tokens = append(tokens, patternToken{kind: literalToken, char: runes[i]})
+		case '[':
+			token, end, err := parseClass(runes, i)
+			if err != nil {
+				return nil, fmt.Errorf("pattern %q: %w", pattern, err)
+			}
+			tokens = append(tokens, token)
+			i = end
+		default:
+			tokens = append(tokens, patternToken{kind: literalToken, char: runes[i]})
+		}
+	}
+
+	return tokens, nil
+}
+
+// parseClass parses the class starting at the `[` at index start. It returns the token and the index
+// of the closing `]`.
+func parseClass(runes []rune, start int) (patternToken, int, error) {
+	token := patternToken{kind: classToken}
+	i := start + 1
+	if i < len(runes) && runes[i] == '!' {
+		token.negated = true
+		i++
+	}
+
+	for ; i < len(runes); i++ {
+		c := runes[i]
+		if c == ']' {
+			if len(token.ranges) == 0 {
+				return token, 0, fmt.Errorf("empty character class at %d", start)
+			}
+			return token, i, nil
+		}
+		if c == '\\' && i+1 < len(runes) {
+			i++
+			c = runes[i]
+		}
+
+		r := charRange{from: c, to: c}
+		if i+2 < len(runes) && runes[i+1] == '-' && runes[i+2] != ']' {
+			r.to = runes[i+2]
+			if r.to < r.from {
+				return token, 0, fmt.Errorf("invalid range %c-%c in character class at %d", r.from, r.to, start)
+			}
+			i += 2
+		}
+		token.ranges = append(token.ranges, r)
+	}
+
+	return token, 0, fmt.Errorf("unclosed character class at %d", start)
+}
@@ -0,0 +1,107 @@
+package chapter17
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrieMatch(t *testing.T) {
+	trie := NewTrie()
+	for _, w := range []string{"cat", "cot", "cut", "cart", "car", "dog", "c?t", "ct"} {
+		trie.Insert(w)
+	}
+
+	testCases := []struct {
+		desc    string
+		pattern string
+		want    []string
+	}{
+		{
+			desc:    "literal",
+			pattern: "cat",
+			want:    []string{"cat"},
+		},
+		{
+			desc:    "single character wildcard",
+			pattern: "c?t",
+			want:    []string{"c?t", "cat", "cot", "cut"},
+		},
+		{
+			desc:    "multi character wildcard",
+			pattern: "ca*",
+			want:    []string{"car", "cart", "cat"},
+		},
+		{
+			desc:    "multi character wildcard matching nothing",
+			pattern: "c*t",
+			want:    []string{"c?t", "cart", "cat", "cot", "ct", "cut"},
+		},
+		{
+			desc:    "repeated multi character wildcards",
+			pattern: "*a**",
+			want:    []string{"car", "cart", "cat"},
+		},
+		{
+			desc:    "character class",
+			pattern: "c[ao]t",
+			want:    []string{"cat", "cot"},
+		},
+		{
+			desc:    "character class range",
+			pattern: "c[n-z]t",
+			want:    []string{"cot", "cut"},
+		},
+		{
+			desc:    "negated character class",
+			pattern: "c[!a]t",
+			want:    []string{"c?t", "cot", "cut"},
+		},
+		{
+			desc:    "escaped wildcard",
+			pattern: `c\?t`,
+			want:    []string{"c?t"},
+		},
+		{
+			desc:    "no match",
+			pattern: "x*",
+			want:    []string{},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := trie.Match(tC.pattern)
+			assert.NoError(t, err)
+			assert.Equal(t, tC.want, got)
+		})
+	}
+}
+
+func TestTrieMatchInvalidPattern(t *testing.T) {
+	trie := NewTrie()
+	trie.Insert("cat")
+	for _, pattern := range []string{"c[at", "c[]t", "c[z-a]t", `cat\`} {
+		_, err := trie.Match(pattern)
+		assert.Error(t, err, pattern)
+	}
+}
+
+func TestTrieMatchStar(t *testing.T) {
+	trie := NewTrie()
+	trie.Insert("cat")
+	trie.Insert("cats")
+
+	// The trie marks the end of a word with a '*' child, which a literal star must never match.
+	got, err := trie.Match(`cat\*`)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+	got, err = trie.Match(`cat[*]`)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+	got, err = trie.Match(`cat[*s]`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cats"}, got)
+	got, err = trie.Match(`cat[!x]`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cats"}, got)
+}
@@ -0,0 +1,156 @@
+package chapter17
+
+import "sort"
+
+type prefixMapNode[V any] struct {
+	children map[rune]*prefixMapNode[V]
+	value    V
+	hasValue bool
+	// count is the number of keys stored in this node and below it.
+	count int
+}
+
+// PrefixMap is a generic trie which maps every word to a value. Each node keeps count of how many
+// keys are below it, so counting the keys sharing a prefix doesn't need to visit them.
+type PrefixMap[V any] struct {
+	root *prefixMapNode[V]
+}
+
+func NewPrefixMap[V any]() *PrefixMap[V] {
+	return &PrefixMap[V]{
+		root: &prefixMapNode[V]{},
+	}
+}
+
+// find returns the node at the end of key or nil if there is no such path.
+func (p *PrefixMap[V]) find(key string) *prefixMapNode[V] {
+	node := p.root
+	for _, c := range key {
+		next, ok := node.children[c]
+		if !ok {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// Put sets the value for key. It returns true if the key was already there and its value got replaced.
+func (p *PrefixMap[V]) Put(key string, value V) bool {
+	if node := p.find(key); node != nil && node.hasValue {
+		node.value = value
+		return true
+	}
+
+	// This is a new key, so every node on the path gets one more key below it.
+	node := p.root
+	node.count++
+	for _, c := range key {
+		next, ok := node.children[c]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[rune]*prefixMapNode[V])
+			}
+			next = &prefixMapNode[V]{}
+			node.children[c] = next
+		}
+		next.count++
+		node = next
+	}
+
+	node.value = value
+	node.hasValue = true
+	return false
+}
+
+// Get returns the value stored for key.
+func (p *PrefixMap[V]) Get(key string) (V, bool) {
+	node := p.find(key)
+	if node == nil || !node.hasValue {
+		var v V
+		return v, false
+	}
+	return node.value, true
+}
+
+// Delete removes key and prunes all the nodes which no longer lead to any key.
+// It returns false if the key wasn't there.
+func (p *PrefixMap[V]) Delete(key string) bool {
+	node := p.find(key)
+	if node == nil || !node.hasValue {
+		return false
+	}
+
+	var zero V
+	node.value = zero
+	node.hasValue = false
+
+	node = p.root
+	node.count--
+	for _, c := range key {
+		next := node.children[c]
+		next.count--
+		if next.count == 0 {
+			// Nothing is left below, so the whole branch can go.
+			delete(node.children, c)
+			return true
+		}
+		node = next
+	}
+
+	return true
+}
+
+// Len returns the number of keys.
+func (p *PrefixMap[V]) Len() int {
+	return p.root.count
+}
+
+// CountPrefix returns the number of keys starting with prefix. It only walks the prefix itself.
+func (p *PrefixMap[V]) CountPrefix(prefix string) int {
+	node := p.find(prefix)
+	if node == nil {
+		return 0
+	}
+	return node.count
+}
+
+// Walk calls fn for every key starting with prefix in ascending order of keys.
+// Walking stops as soon as fn returns false.
+func (p *PrefixMap[V]) Walk(prefix string, fn func(key string, value V) bool) {
+	node := p.find(prefix)
+	if node == nil {
+		return
+	}
+	node.walk([]rune(prefix), fn)
+}
+
+func (n *prefixMapNode[V]) walk(key []rune, fn func(key string, value V) bool) bool {
+	if n.hasValue && !fn(string(key), n.value) {
+		return false
+	}
+
+	runes := make([]rune, 0, len(n.children))
+	for c := range n.children {
+		runes = append(runes, c)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+	for _, c := range runes {
+		if !n.children[c].walk(append(key, c), fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Keys returns all keys starting with prefix in ascending order.
+func (p *PrefixMap[V]) Keys(prefix string) []string {
+	var keys []string
+	p.Walk(prefix, func(key string, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
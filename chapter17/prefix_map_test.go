@@ -0,0 +1,78 @@
+package chapter17
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixMapPutGet(t *testing.T) {
+	m := NewPrefixMap[int]()
+	assert.False(t, m.Put("cat", 1))
+	assert.False(t, m.Put("car", 2))
+	assert.True(t, m.Put("cat", 3))
+	assert.False(t, m.Put("", 4))
+
+	v, ok := m.Get("cat")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	v, ok = m.Get("")
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+	_, ok = m.Get("ca")
+	assert.False(t, ok)
+	_, ok = m.Get("dog")
+	assert.False(t, ok)
+	assert.Equal(t, 3, m.Len())
+}
+
+func TestPrefixMapCountPrefix(t *testing.T) {
+	m := NewPrefixMap[string]()
+	for _, w := range []string{"bad", "bat", "batter", "cat", "ace"} {
+		m.Put(w, w)
+	}
+	m.Put("bat", "again")
+	assert.Equal(t, 5, m.CountPrefix(""))
+	assert.Equal(t, 3, m.CountPrefix("ba"))
+	assert.Equal(t, 2, m.CountPrefix("bat"))
+	assert.Equal(t, 1, m.CountPrefix("batt"))
+	assert.Equal(t, 0, m.CountPrefix("x"))
+}
+
+func TestPrefixMapDelete(t *testing.T) {
+	m := NewPrefixMap[int]()
+	m.Put("bat", 1)
+	m.Put("batter", 2)
+	m.Put("cat", 3)
+	assert.False(t, m.Delete("batt"))
+	assert.False(t, m.Delete("dog"))
+
+	assert.True(t, m.Delete("batter"))
+	assert.False(t, m.Delete("batter"))
+	assert.Equal(t, 2, m.Len())
+	assert.Equal(t, 1, m.CountPrefix("b"))
+	// The "ter" branch is pruned.
+	assert.Empty(t, m.find("bat").children)
+
+	assert.True(t, m.Delete("bat"))
+	assert.Nil(t, m.find("b"))
+	assert.Equal(t, 1, m.Len())
+	assert.Equal(t, []string{"cat"}, m.Keys(""))
+}
+
+func TestPrefixMapWalk(t *testing.T) {
+	m := NewPrefixMap[int]()
+	for i, w := range []string{"bad", "bat", "batter", "cat", "ace", "ba"} {
+		m.Put(w, i)
+	}
+	assert.Equal(t, []string{"ace", "ba", "bad", "bat", "batter", "cat"}, m.Keys(""))
+	assert.Equal(t, []string{"bat", "batter"}, m.Keys("bat"))
+	assert.Nil(t, m.Keys("x"))
+
+	var visited []string
+	m.Walk("b", func(key string, value int) bool {
+		visited = append(visited, key)
+		return len(visited) < 2
+	})
+	assert.Equal(t, []string{"ba", "bad"}, visited)
+}
@@ -0,0 +1,251 @@
+package chapter17
+
+import (
+	"sort"
+	"strings"
+)
+
+// Dictionary is the set of operations shared by the Trie and the RadixTree so one can be
+// swapped for the other.
+type Dictionary interface {
+	Add(word string)
+	Contains(word string) bool
+	Delete(word string) bool
+	AutoComplete(prefix string) []string
+}
+
+var (
+	_ Dictionary = (*Trie)(nil)
+	_ Dictionary = (*RadixTree)(nil)
+)
+
+// RadixNode is a node of a compressed trie. Instead of a single character every edge carries
+// a whole label, which is the run of characters that don't branch anywhere.
+type RadixNode struct {
+	Label string
+	// Children are ordered by the first byte of their label. No two children share a first byte.
+	Children []*RadixNode
+	IsWord   bool
+}
+
+// RadixTree (also called a Patricia trie) is a trie where chains of nodes with a single child
+// are merged into one node. This keeps the node count proportional to the number of words
+// instead of the number of characters.
+type RadixTree struct {
+	Root *RadixNode
+}
+
+func NewRadixTree() *RadixTree {
+	return &RadixTree{
+		Root: &RadixNode{},
+	}
+}
+
+// child returns the index of the child whose label starts with b, and whether it exists.
+// If it doesn't, the index is where such a child should be inserted.
+func (n *RadixNode) child(b byte) (int, bool) {
+	i := sort.Search(len(n.Children), func(i int) bool {
+		return n.Children[i].Label[0] >= b
+	})
+	return i, i < len(n.Children) && n.Children[i].Label[0] == b
+}
+
+// Add inserts a word into the tree.
+func (r *RadixTree) Add(word string) {
+	node := r.Root
+	for {
+		if word == "" {
+			node.IsWord = true
+			return
+		}
+
+		i, ok := node.child(word[0])
+		if !ok {
+			// Nothing shares this path, so the rest of the word becomes a single new edge.
+			node.Children = append(node.Children, nil)
+			copy(node.Children[i+1:], node.Children[i:])
+			node.Children[i] = &RadixNode{Label: word, IsWord: true}
+			return
+		}
+
+		next := node.Children[i]
+		common := commonPrefixLength(word, next.Label)
+		if common < len(next.Label) {
+			// The word diverges in the middle of the edge. Split the edge at the point of divergence.
+			split := &RadixNode{
+				Label:    next.Label[:common],
+				Children: []*RadixNode{next},
+			}
+			next.Label = next.Label[common:]
+			node.Children[i] = split
+			next = split
+		}
+
+		node = next
+		word = word[common:]
+	}
+}
+
+// Contains returns true if the exact word was added to the tree.
+func (r *RadixTree) Contains(word string) bool {
+	node, rest := r.find(word)
+	return node != nil && rest == "" && node.IsWord
+}
+
+// find walks the tree as far as the word goes. It returns the node reached and, if the word ended
+// in the middle of an edge, the part of that edge's label which is still left.
+// It returns nil if the word isn't a prefix of anything in the tree.
+func (r *RadixTree) find(word string) (*RadixNode, string) {
+	node := r.Root
+	for word != "" {
+		i, ok := node.child(word[0])
+		if !ok {
+			return nil, ""
+		}
+
+		next := node.Children[i]
+		common := commonPrefixLength(word, next.Label)
+		if common == len(word) {
+			return next, next.Label[common:]
+		}
+		if common < len(next.Label) {
+			return nil, ""
+		}
+
+		node = next
+		word = word[common:]
+	}
+
+	return node, ""
+}
+
+// Delete removes a word from the tree, merging nodes back together where the word
+// was the reason for a split. It returns false if the word wasn't in the tree.
+func (r *RadixTree) Delete(word string) bool {
+	var (
+		parent *RadixNode
+		index  int
+	)
+	node := r.Root
+	for word != "" {
+		i, ok := node.child(word[0])
+		if !ok {
+			return false
+		}
+
+		next := node.Children[i]
+		if !strings.HasPrefix(word, next.Label) {
+			return false
+		}
+
+		parent, index, node = node, i, next
+		word = word[len(next.Label):]
+	}
+
+	if !node.IsWord {
+		return false
+	}
+	node.IsWord = false
+
+	// The root is never removed or merged.
+	if parent == nil {
+		return true
+	}
+
+	switch len(node.Children) {
+	case 0:
+		parent.Children = append(parent.Children[:index], parent.Children[index+1:]...)
+		// The parent might have been a split point which is now left with a single child.
+		if parent != r.Root && !parent.IsWord && len(parent.Children) == 1 {
+			parent.merge()
+		}
+	case 1:
+		node.merge()
+	}
+
+	return true
+}
+
+// merge folds the only child of the node into the node itself.
+func (n *RadixNode) merge() {
+	child := n.Children[0]
+	n.Label += child.Label
+	n.IsWord = child.IsWord
+	n.Children = child.Children
+}
+
+// AutoComplete returns the rest of every word starting with prefix. Just like the Trie, the
+// calling code must add the prefix back if it wants to display full words.
+func (r *RadixTree) AutoComplete(prefix string) []string {
+	node, rest := r.find(prefix)
+	if node == nil {
+		return nil
+	}
+
+	return node.collect(rest, []string{})
+}
+
+func (n *RadixNode) collect(word string, words []string) []string {
+	if n.IsWord {
+		words = append(words, word)
+	}
+
+	for _, c := range n.Children {
+		words = c.collect(word+c.Label, words)
+	}
+
+	return words
+}
+
+// LongestPrefix returns the longest word in the tree which is a prefix of s.
+// For example, with "cat" and "catnip" in the tree, the longest prefix of "catnap" is "cat".
+func (r *RadixTree) LongestPrefix(s string) (string, bool) {
+	var (
+		longest string
+		found   = r.Root.IsWord
+	)
+
+	node := r.Root
+	consumed := 0
+	for consumed < len(s) {
+		i, ok := node.child(s[consumed])
+		if !ok {
+			break
+		}
+
+		next := node.Children[i]
+		if !strings.HasPrefix(s[consumed:], next.Label) {
+			break
+		}
+
+		consumed += len(next.Label)
+		node = next
+		if node.IsWord {
+			longest = s[:consumed]
+			found = true
+		}
+	}
+
+	return longest, found
+}
+
+// NodeCount returns the number of nodes in the tree including the root.
+func (r *RadixTree) NodeCount() int {
+	return r.Root.count()
+}
+
+func (n *RadixNode) count() int {
+	c := 1
+	for _, child := range n.Children {
+		c += child.count()
+	}
+	return c
+}
+
+func commonPrefixLength(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
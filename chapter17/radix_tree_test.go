@@ -0,0 +1,179 @@
+package chapter17
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRadixTreeAdd(t *testing.T) {
+	tree := NewRadixTree()
+	tree.Add("romane")
+	tree.Add("romanus")
+	tree.Add("romulus")
+	tree.Add("rom")
+	assert.Equal(t, &RadixNode{
+		Children: []*RadixNode{
+			{
+				Label:  "rom",
+				IsWord: true,
+				Children: []*RadixNode{
+					{
+						Label: "an",
+						Children: []*RadixNode{
+							{Label: "e", IsWord: true},
+							{Label: "us", IsWord: true},
+						},
+					},
+					{Label: "ulus", IsWord: true},
+				},
+			},
+		},
+	}, tree.Root)
+	assert.Equal(t, 6, tree.NodeCount())
+}
+
+func TestRadixTreeContains(t *testing.T) {
+	tree := NewRadixTree()
+	tree.Add("bat")
+	tree.Add("batter")
+	assert.True(t, tree.Contains("bat"))
+	assert.True(t, tree.Contains("batter"))
+	assert.False(t, tree.Contains("batt"))
+	assert.False(t, tree.Contains("ba"))
+	assert.False(t, tree.Contains("cat"))
+	assert.False(t, tree.Contains(""))
+}
+
+func TestRadixTreeAutoComplete(t *testing.T) {
+	tree := NewRadixTree()
+	tree.Add("ace")
+	tree.Add("bad")
+	tree.Add("cat")
+	tree.Add("bat")
+	tree.Add("batter")
+	assert.Equal(t, []string{"d", "t", "tter"}, tree.AutoComplete("ba"))
+	assert.Equal(t, []string{"", "ter"}, tree.AutoComplete("bat"))
+	assert.Equal(t, []string{"er"}, tree.AutoComplete("batt"))
+	assert.Nil(t, tree.AutoComplete("bx"))
+}
+
+func TestRadixTreeDelete(t *testing.T) {
+	tree := NewRadixTree()
+	tree.Add("romane")
+	tree.Add("romanus")
+	tree.Add("rom")
+	assert.False(t, tree.Delete("roman"))
+	assert.False(t, tree.Delete("romanesque"))
+
+	assert.True(t, tree.Delete("romanus"))
+	assert.False(t, tree.Contains("romanus"))
+	assert.Equal(t, &RadixNode{
+		Children: []*RadixNode{
+			{
+				Label:    "rom",
+				IsWord:   true,
+				Children: []*RadixNode{{Label: "ane", IsWord: true}},
+			},
+		},
+	}, tree.Root)
+
+	assert.True(t, tree.Delete("rom"))
+	assert.Equal(t, &RadixNode{
+		Children: []*RadixNode{{Label: "romane", IsWord: true}},
+	}, tree.Root)
+
+	assert.True(t, tree.Delete("romane"))
+	assert.Equal(t, &RadixNode{Children: []*RadixNode{}}, tree.Root)
+}
+
+func TestRadixTreeLongestPrefix(t *testing.T) {
+	tree := NewRadixTree()
+	tree.Add("cat")
+	tree.Add("catnip")
+	tree.Add("dog")
+	prefix, ok := tree.LongestPrefix("catnap")
+	assert.True(t, ok)
+	assert.Equal(t, "cat", prefix)
+	prefix, ok = tree.LongestPrefix("catnipping")
+	assert.True(t, ok)
+	assert.Equal(t, "catnip", prefix)
+	_, ok = tree.LongestPrefix("ca")
+	assert.False(t, ok)
+	_, ok = tree.LongestPrefix("do")
+	assert.False(t, ok)
+}
+
+func TestDictionaryImplementationsAgree(t *testing.T) {
+	words := []string{"a", "ab", "abc", "abd", "b", "bcd", "bce", "héllo", "hèllo"}
+	for _, d := range []Dictionary{NewTrie(), NewRadixTree()} {
+		for _, w := range words {
+			d.Add(w)
+		}
+		assert.True(t, d.Delete("abc"))
+		assert.False(t, d.Delete("abc"))
+
+		result := d.AutoComplete("")
+		sort.Strings(result)
+		assert.Equal(t, []string{"a", "ab", "abd", "b", "bcd", "bce", "hèllo", "héllo"}, result)
+		assert.True(t, d.Contains("héllo"))
+		assert.False(t, d.Contains("h"))
+
+		// The trie marks the end of a word with a '*' child, which must never be walked into.
+		for _, w := range []string{"ab*", "ab*x", "*"} {
+			assert.False(t, d.Contains(w), w)
+			assert.False(t, d.Delete(w), w)
+		}
+		assert.True(t, d.Contains("ab"))
+	}
+}
+
+// dictionaryWords generates URL like words which share long prefixes, like a real dictionary would.
+func dictionaryWords(n int) []string {
+	words := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		words = append(words, fmt.Sprintf("https://example.com/section-%d/article-%d", i%100, i))
+	}
+	return words
+}
+
+func TestRadixTreeUsesFewerNodes(t *testing.T) {
+	words := dictionaryWords(10000)
+	trie := NewTrie()
+	tree := NewRadixTree()
+	for _, w := range words {
+		trie.Add(w)
+		tree.Add(w)
+	}
+	// Every character of a chain without branches is its own node in the trie, but a single edge in the
+	// radix tree.
+	assert.Less(t, tree.NodeCount()*2, trie.NodeCount())
+}
+
+func BenchmarkTrieAdd(b *testing.B) {
+	words := dictionaryWords(10000)
+	b.ReportAllocs()
+	var trie *Trie
+	for i := 0; i < b.N; i++ {
+		trie = NewTrie()
+		for _, w := range words {
+			trie.Add(w)
+		}
+	}
+	b.ReportMetric(float64(trie.NodeCount()), "nodes")
+}
+
+func BenchmarkRadixTreeAdd(b *testing.B) {
+	words := dictionaryWords(10000)
+	b.ReportAllocs()
+	var tree *RadixTree
+	for i := 0; i < b.N; i++ {
+		tree = NewRadixTree()
+		for _, w := range words {
+			tree.Add(w)
+		}
+	}
+	b.ReportMetric(float64(tree.NodeCount()), "nodes")
+}
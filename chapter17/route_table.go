@@ -0,0 +1,210 @@
+package chapter17
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+type routeNode[V any] struct {
+	// children are indexed by the next bit of the address.
+	children [2]*routeNode[V]
+	route    Route[V]
+	hasRoute bool
+}
+
+// Route is a prefix stored in a RouteTable with its value.
+type Route[V any] struct {
+	Prefix netip.Prefix
+	Value  V
+}
+
+// RouteTable is a binary trie keyed on the bits of IP prefixes. Instead of a character, every level
+// of the trie is a single bit of the address, so a /24 is stored 24 levels deep. IPv4 and IPv6
+// prefixes live in separate tries.
+type RouteTable[V any] struct {
+	v4 *routeNode[V]
+	v6 *routeNode[V]
+}
+
+func NewRouteTable[V any]() *RouteTable[V] {
+	return &RouteTable[V]{
+		v4: &routeNode[V]{},
+		v6: &routeNode[V]{},
+	}
+}
+
+func (r *RouteTable[V]) root(addr netip.Addr) *routeNode[V] {
+	if addr.Is4() {
+		return r.v4
+	}
+	return r.v6
+}
+
+// bit returns the i-th bit of the address counting from the most significant one.
+func bit(address []byte, i int) int {
+	return int(address[i/8]>>(7-i%8)) & 1
+}
+
+// normalize masks the host bits of a prefix and turns IPv4 mapped IPv6 prefixes into IPv4 ones.
+func normalize(prefix netip.Prefix) (netip.Prefix, error) {
+	if !prefix.IsValid() {
+		return prefix, fmt.Errorf("invalid prefix %s", prefix)
+	}
+
+	addr := prefix.Addr()
+	if addr.Is4In6() {
+		if prefix.Bits() < 96 {
+			return prefix, fmt.Errorf("prefix %s is shorter than the IPv4 mapped range", prefix)
+		}
+		prefix = netip.PrefixFrom(addr.Unmap(), prefix.Bits()-96)
+	}
+
+	return prefix.Masked(), nil
+}
+
+// Insert stores a value for a prefix. The host bits of the prefix are ignored, so 10.1.2.3/8 is
+// the same as 10.0.0.0/8. It returns true if the prefix was already there and its value got replaced.
+func (r *RouteTable[V]) Insert(prefix netip.Prefix, value V) (bool, error) {
+	prefix, err := normalize(prefix)
+	if err != nil {
+		return false, err
+	}
+
+	address := prefix.Addr().AsSlice()
+	node := r.root(prefix.Addr())
+	for i := 0; i < prefix.Bits(); i++ {
+		b := bit(address, i)
+		if node.children[b] == nil {
+			node.children[b] = &routeNode[V]{}
+		}
+		node = node.children[b]
+	}
+
+	replaced := node.hasRoute
+	node.route = Route[V]{Prefix: prefix, Value: value}
+	node.hasRoute = true
+	return replaced, nil
+}
+
+// Delete removes a prefix and prunes the nodes which no longer lead to any prefix.
+// It returns false if the prefix wasn't there.
+func (r *RouteTable[V]) Delete(prefix netip.Prefix) bool {
+	prefix, err := normalize(prefix)
+	if err != nil {
+		return false
+	}
+
+	address := prefix.Addr().AsSlice()
+	path := []*routeNode[V]{r.root(prefix.Addr())}
+	for i := 0; i < prefix.Bits(); i++ {
+		next := path[i].children[bit(address, i)]
+		if next == nil {
+			return false
+		}
+		path = append(path, next)
+	}
+
+	node := path[len(path)-1]
+	if !node.hasRoute {
+		return false
+	}
+	node.route = Route[V]{}
+	node.hasRoute = false
+
+	for i := len(path) - 1; i > 0; i-- {
+		n := path[i]
+		if n.hasRoute || n.children[0] != nil || n.children[1] != nil {
+			break
+		}
+		path[i-1].children[bit(address, i-1)] = nil
+	}
+
+	return true
+}
+
+// Get returns the value stored for exactly this prefix.
+func (r *RouteTable[V]) Get(prefix netip.Prefix) (V, bool) {
+	var zero V
+	prefix, err := normalize(prefix)
+	if err != nil {
+		return zero, false
+	}
+
+	node := r.find(prefix)
+	if node == nil || !node.hasRoute {
+		return zero, false
+	}
+	return node.route.Value, true
+}
+
+func (r *RouteTable[V]) find(prefix netip.Prefix) *routeNode[V] {
+	address := prefix.Addr().AsSlice()
+	node := r.root(prefix.Addr())
+	for i := 0; i < prefix.Bits() && node != nil; i++ {
+		node = node.children[bit(address, i)]
+	}
+	return node
+}
+
+// Lookup returns the most specific route containing the address, which is what a router would use.
+func (r *RouteTable[V]) Lookup(addr netip.Addr) (Route[V], bool) {
+	if !addr.IsValid() {
+		return Route[V]{}, false
+	}
+
+	covering := r.Covering(netip.PrefixFrom(addr, addr.BitLen()))
+	if len(covering) == 0 {
+		return Route[V]{}, false
+	}
+	return covering[len(covering)-1], true
+}
+
+// Covering returns every route containing the prefix, including the prefix itself, from the least
+// to the most specific.
+func (r *RouteTable[V]) Covering(prefix netip.Prefix) []Route[V] {
+	prefix, err := normalize(prefix)
+	if err != nil {
+		return nil
+	}
+
+	var routes []Route[V]
+	address := prefix.Addr().AsSlice()
+	node := r.root(prefix.Addr())
+	for i := 0; node != nil; i++ {
+		if node.hasRoute {
+			routes = append(routes, node.route)
+		}
+		if i == prefix.Bits() {
+			break
+		}
+		node = node.children[bit(address, i)]
+	}
+
+	return routes
+}
+
+// Covered returns every route inside the prefix, including the prefix itself, in depth first order.
+func (r *RouteTable[V]) Covered(prefix netip.Prefix) []Route[V] {
+	prefix, err := normalize(prefix)
+	if err != nil {
+		return nil
+	}
+
+	node := r.find(prefix)
+	if node == nil {
+		return nil
+	}
+	return node.collect(nil)
+}
+
+func (n *routeNode[V]) collect(routes []Route[V]) []Route[V] {
+	if n.hasRoute {
+		routes = append(routes, n.route)
+	}
+	for _, c := range n.children {
+		if c != nil {
+			routes = c.collect(routes)
+		}
+	}
+	return routes
+}
@@ -0,0 +1,107 @@
+package chapter17
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRouteTable(t *testing.T) *RouteTable[string] {
+	r := NewRouteTable[string]()
+	for prefix, hop := range map[string]string{
+		"0.0.0.0/0":      "default",
+		"10.0.0.0/8":     "a",
+		"10.1.0.0/16":    "b",
+		"10.1.2.0/24":    "c",
+		"192.168.0.0/16": "d",
+		"2001:db8::/32":  "e",
+		"::/0":           "default6",
+	} {
+		_, err := r.Insert(netip.MustParsePrefix(prefix), hop)
+		require.NoError(t, err)
+	}
+	return r
+}
+
+func TestRouteTableLookup(t *testing.T) {
+	r := newTestRouteTable(t)
+	testCases := []struct {
+		addr   string
+		prefix string
+		want   string
+	}{
+		{addr: "10.1.2.3", prefix: "10.1.2.0/24", want: "c"},
+		{addr: "10.1.3.3", prefix: "10.1.0.0/16", want: "b"},
+		{addr: "10.2.0.1", prefix: "10.0.0.0/8", want: "a"},
+		{addr: "8.8.8.8", prefix: "0.0.0.0/0", want: "default"},
+		{addr: "::ffff:10.1.2.3", prefix: "10.1.2.0/24", want: "c"},
+		{addr: "2001:db8::1", prefix: "2001:db8::/32", want: "e"},
+		{addr: "2001:db9::1", prefix: "::/0", want: "default6"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.addr, func(t *testing.T) {
+			route, ok := r.Lookup(netip.MustParseAddr(tC.addr))
+			assert.True(t, ok)
+			assert.Equal(t, Route[string]{Prefix: netip.MustParsePrefix(tC.prefix), Value: tC.want}, route)
+		})
+	}
+
+	_, ok := NewRouteTable[string]().Lookup(netip.MustParseAddr("10.0.0.1"))
+	assert.False(t, ok)
+}
+
+func TestRouteTableInsert(t *testing.T) {
+	r := NewRouteTable[int]()
+	replaced, err := r.Insert(netip.MustParsePrefix("10.1.2.3/8"), 1)
+	require.NoError(t, err)
+	assert.False(t, replaced)
+	replaced, err = r.Insert(netip.MustParsePrefix("10.0.0.0/8"), 2)
+	require.NoError(t, err)
+	assert.True(t, replaced)
+
+	v, ok := r.Get(netip.MustParsePrefix("10.0.0.0/8"))
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	_, ok = r.Get(netip.MustParsePrefix("10.0.0.0/9"))
+	assert.False(t, ok)
+
+	_, err = r.Insert(netip.Prefix{}, 3)
+	assert.Error(t, err)
+}
+
+func TestRouteTableDelete(t *testing.T) {
+	r := newTestRouteTable(t)
+	assert.False(t, r.Delete(netip.MustParsePrefix("10.1.0.0/17")))
+	assert.True(t, r.Delete(netip.MustParsePrefix("10.1.2.0/24")))
+	assert.False(t, r.Delete(netip.MustParsePrefix("10.1.2.0/24")))
+
+	route, ok := r.Lookup(netip.MustParseAddr("10.1.2.3"))
+	assert.True(t, ok)
+	assert.Equal(t, "b", route.Value)
+	// The branch below the /16 is pruned.
+	node := r.find(netip.MustParsePrefix("10.1.0.0/16"))
+	assert.Equal(t, [2]*routeNode[string]{}, node.children)
+}
+
+func TestRouteTableCovering(t *testing.T) {
+	r := newTestRouteTable(t)
+	routes := r.Covering(netip.MustParsePrefix("10.1.2.0/24"))
+	var prefixes []string
+	for _, route := range routes {
+		prefixes = append(prefixes, route.Prefix.String())
+	}
+	assert.Equal(t, []string{"0.0.0.0/0", "10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24"}, prefixes)
+}
+
+func TestRouteTableCovered(t *testing.T) {
+	r := newTestRouteTable(t)
+	routes := r.Covered(netip.MustParsePrefix("10.0.0.0/8"))
+	var prefixes []string
+	for _, route := range routes {
+		prefixes = append(prefixes, route.Prefix.String())
+	}
+	assert.Equal(t, []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24"}, prefixes)
+	assert.Nil(t, r.Covered(netip.MustParsePrefix("172.16.0.0/12")))
+}
@@ -0,0 +1,254 @@
+package chapter17
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+	"strings"
+)
+
+// The binary format is a header, the entries and a checksum:
+//
+//	magic "TRIE" | version (1 byte) | kind (1 byte) | number of entries (uvarint)
+//	entry: shared prefix length (uvarint) | suffix length (uvarint) | suffix | payload length (uvarint) | payload
+//	CRC-32 (IEEE) of everything above, 4 bytes big endian
+//
+// Entries are sorted by word and only store the part which differs from the word before them,
+// so the shared prefixes which make a trie worth having don't take up any extra space in the file.
+const (
+	serializeMagic   = "TRIE"
+	serializeVersion = 1
+)
+
+const (
+	trieKind byte = iota + 1
+	prefixMapKind
+)
+
+var (
+	// ErrCorrupted is returned when the checksum doesn't match or the data can't be parsed.
+	ErrCorrupted = errors.New("corrupted trie data")
+	// ErrUnsupportedVersion is returned for data written by a newer version of the format.
+	ErrUnsupportedVersion = errors.New("unsupported trie data version")
+)
+
+type entry struct {
+	word    string
+	payload []byte
+}
+
+func writeEntries(w io.Writer, kind byte, entries []entry) (int64, error) {
+	var buf bytes.Buffer
+	buf.WriteString(serializeMagic)
+	buf.WriteByte(serializeVersion)
+	buf.WriteByte(kind)
+	buf.Write(binary.AppendUvarint(nil, uint64(len(entries))))
+
+	previous := ""
+	for _, e := range entries {
+		shared := commonPrefixLength(previous, e.word)
+		buf.Write(binary.AppendUvarint(nil, uint64(shared)))
+		buf.Write(binary.AppendUvarint(nil, uint64(len(e.word)-shared)))
+		buf.WriteString(e.word[shared:])
+		buf.Write(binary.AppendUvarint(nil, uint64(len(e.payload))))
+		buf.Write(e.payload)
+		previous = e.word
+	}
+
+	buf.Write(binary.BigEndian.AppendUint32(nil, crc32.ChecksumIEEE(buf.Bytes())))
+	return buf.WriteTo(w)
+}
+
+func readEntries(r io.Reader, kind byte) ([]entry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	headerLength := len(serializeMagic) + 2
+	if len(data) < headerLength+4 || string(data[:len(serializeMagic)]) != serializeMagic {
+		return nil, fmt.Errorf("%w: missing header", ErrCorrupted)
+	}
+	if v := data[len(serializeMagic)]; v != serializeVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, v)
+	}
+	if k := data[len(serializeMagic)+1]; k != kind {
+		return nil, fmt.Errorf("%w: unexpected kind %d", ErrCorrupted, k)
+	}
+
+	body, checksum := data[:len(data)-4], binary.BigEndian.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(body) != checksum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorrupted)
+	}
+
+	reader := bytes.NewReader(body[headerLength:])
+	count, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+
+	var entries []entry
+	previous := ""
+	for i := uint64(0); i < count; i++ {
+		shared, err := binary.ReadUvarint(reader)
+		if err != nil || shared > uint64(len(previous)) {
+			return nil, fmt.Errorf("%w: invalid entry %d", ErrCorrupted, i)
+		}
+		suffix, err := readBytes(reader)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid entry %d", ErrCorrupted, i)
+		}
+		payload, err := readBytes(reader)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid entry %d", ErrCorrupted, i)
+		}
+
+		word := previous[:shared] + string(suffix)
+		entries = append(entries, entry{word: word, payload: payload})
+		previous = word
+	}
+
+	if reader.Len() != 0 {
+		return nil, fmt.Errorf("%w: trailing data", ErrCorrupted)
+	}
+
+	return entries, nil
+}
+
+// readBytes reads a length prefixed byte slice.
+func readBytes(r *bytes.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := make([]byte, n)
+	_, err = io.ReadFull(r, b)
+	return b, err
+}
+
+// WriteTo writes the trie, including how often each word was inserted, in the binary format.
+func (t *Trie) WriteTo(w io.Writer) (int64, error) {
+	words := t.CollectAllWords("", nil, []string{})
+	sort.Strings(words)
+
+	entries := make([]entry, 0, len(words))
+	for _, word := range words {
+		frequency := t.Search(word).Frequency
+		entries = append(entries, entry{word: word, payload: binary.AppendUvarint(nil, uint64(frequency))})
+	}
+
+	return writeEntries(w, trieKind, entries)
+}
+
+// ReadTrie loads a trie written by WriteTo.
+func ReadTrie(r io.Reader) (*Trie, error) {
+	entries, err := readEntries(r, trieKind)
+	if err != nil {
+		return nil, err
+	}
+
+	t := NewTrie()
+	for _, e := range entries {
+		if strings.ContainsRune(e.word, '*') {
+			return nil, fmt.Errorf("%w: word %q contains the end-of-word marker", ErrCorrupted, e.word)
+		}
+		frequency, n := binary.Uvarint(e.payload)
+		if n != len(e.payload) {
+			return nil, fmt.Errorf("%w: invalid frequency for %q", ErrCorrupted, e.word)
+		}
+		t.Insert(e.word).Frequency = int(frequency)
+	}
+
+	return t, nil
+}
+
+// ExportWords writes every word of the trie on its own line in alphabetical order.
+func (t *Trie) ExportWords(w io.Writer) error {
+	words := t.CollectAllWords("", nil, []string{})
+	sort.Strings(words)
+
+	bw := bufio.NewWriter(w)
+	for _, word := range words {
+		if _, err := bw.WriteString(word + "\n"); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ImportWords inserts every line of r as a word. Surrounding whitespace is trimmed and empty lines are skipped.
+// Words can't contain '*', which marks the end of a word in the trie. If any line does, or reading fails,
+// nothing is inserted.
+func (t *Trie) ImportWords(r io.Reader) error {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" {
+			continue
+		}
+		if strings.ContainsRune(word, '*') {
+			return fmt.Errorf("line %d: word %q contains the end-of-word marker '*'", line, word)
+		}
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	for _, word := range words {
+		t.Insert(word)
+	}
+	return nil
+}
+
+// Encode writes the map in the binary format. The values are turned into bytes by encode.
+func (p *PrefixMap[V]) Encode(w io.Writer, encode func(V) ([]byte, error)) error {
+	var (
+		entries []entry
+		err     error
+	)
+	p.Walk("", func(key string, value V) bool {
+		var payload []byte
+		if payload, err = encode(value); err != nil {
+			err = fmt.Errorf("failed to encode value of %q: %w", key, err)
+			return false
+		}
+		entries = append(entries, entry{word: key, payload: payload})
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = writeEntries(w, prefixMapKind, entries)
+	return err
+}
+
+// DecodePrefixMap loads a map written by Encode. The values are restored from bytes by decode.
+func DecodePrefixMap[V any](r io.Reader, decode func([]byte) (V, error)) (*PrefixMap[V], error) {
+	entries, err := readEntries(r, prefixMapKind)
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewPrefixMap[V]()
+	for _, e := range entries {
+		value, err := decode(e.payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode value of %q: %w", e.word, err)
+		}
+		p.Put(e.word, value)
+	}
+
+	return p, nil
+}
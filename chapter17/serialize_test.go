@@ -0,0 +1,116 @@
+package chapter17
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrieWriteToReadTrie(t *testing.T) {
+	trie := NewTrie()
+	for _, w := range []string{"bat", "batter", "bad", "cat", "cat", "héllo"} {
+		trie.Insert(w)
+	}
+
+	var buf bytes.Buffer
+	n, err := trie.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	loaded, err := ReadTrie(&buf)
+	require.NoError(t, err)
+	result := loaded.CollectAllWords("", nil, []string{})
+	sort.Strings(result)
+	assert.Equal(t, []string{"bad", "bat", "batter", "cat", "héllo"}, result)
+	assert.Equal(t, 2, loaded.Search("cat").Frequency)
+	assert.Equal(t, 1, loaded.Search("bat").Frequency)
+}
+
+func TestReadTrieCorrupted(t *testing.T) {
+	trie := NewTrie()
+	trie.Insert("cat")
+	var buf bytes.Buffer
+	_, err := trie.WriteTo(&buf)
+	require.NoError(t, err)
+	data := buf.Bytes()
+
+	flipped := append([]byte{}, data...)
+	flipped[len(flipped)-6] ^= 0xff
+	_, err = ReadTrie(bytes.NewReader(flipped))
+	assert.True(t, errors.Is(err, ErrCorrupted), err)
+
+	_, err = ReadTrie(bytes.NewReader(data[:len(data)-1]))
+	assert.True(t, errors.Is(err, ErrCorrupted), err)
+
+	_, err = ReadTrie(strings.NewReader("nope"))
+	assert.True(t, errors.Is(err, ErrCorrupted), err)
+
+	newer := append([]byte{}, data...)
+	newer[4] = 2
+	_, err = ReadTrie(bytes.NewReader(newer))
+	assert.True(t, errors.Is(err, ErrUnsupportedVersion), err)
+
+	// The checksum is fine, but '*' can't be part of a word.
+	var marker bytes.Buffer
+	_, err = writeEntries(&marker, trieKind, []entry{{word: "ca*x", payload: []byte{1}}})
+	require.NoError(t, err)
+	_, err = ReadTrie(&marker)
+	assert.True(t, errors.Is(err, ErrCorrupted), err)
+
+	// A prefix map is not a trie.
+	m := NewPrefixMap[int]()
+	var mapBuf bytes.Buffer
+	require.NoError(t, m.Encode(&mapBuf, func(int) ([]byte, error) { return nil, nil }))
+	_, err = ReadTrie(&mapBuf)
+	assert.True(t, errors.Is(err, ErrCorrupted), err)
+}
+
+func TestTrieExportImportWords(t *testing.T) {
+	trie := NewTrie()
+	err := trie.ImportWords(strings.NewReader("cat\n\n  bat \r\nbatter\n"))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, trie.ExportWords(&buf))
+	assert.Equal(t, "bat\nbatter\ncat\n", buf.String())
+
+	assert.EqualError(t, trie.ImportWords(strings.NewReader("ca\nca*x\n")), `line 2: word "ca*x" contains the end-of-word marker '*'`)
+	assert.False(t, trie.Contains("ca"))
+}
+
+func TestPrefixMapEncodeDecode(t *testing.T) {
+	m := NewPrefixMap[int]()
+	m.Put("bat", 1)
+	m.Put("batter", 200)
+	m.Put("cat", 3)
+
+	var buf bytes.Buffer
+	err := m.Encode(&buf, func(v int) ([]byte, error) {
+		return []byte(strconv.Itoa(v)), nil
+	})
+	require.NoError(t, err)
+
+	loaded, err := DecodePrefixMap(&buf, func(b []byte) (int, error) {
+		return strconv.Atoi(string(b))
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, loaded.Len())
+	v, ok := loaded.Get("batter")
+	assert.True(t, ok)
+	assert.Equal(t, 200, v)
+}
+
+func TestPrefixMapEncodeError(t *testing.T) {
+	m := NewPrefixMap[int]()
+	m.Put("bat", 1)
+	err := m.Encode(&bytes.Buffer{}, func(v int) ([]byte, error) {
+		return nil, errors.New("boom")
+	})
+	assert.EqualError(t, err, `failed to encode value of "bat": boom`)
+}
@@ -1,7 +1,14 @@
 package chapter17
 
+import (
+	"sort"
+	"unicode/utf8"
+)
+
 type TrieNode struct {
 	Children map[rune]*TrieNode
+	// Frequency counts how many times the word ending at this node was inserted.
+	Frequency int
 }
 
 type Trie struct {
@@ -21,7 +28,8 @@ func (t *Trie) Search(word string) *TrieNode {
 	currentNode := t.Root
 
 	for _, c := range word {
-		if v, ok := currentNode.Children[c]; ok {
+		// The '*' child only marks the end of a word and has no node, so it can't be walked into.
+		if v, ok := currentNode.Children[c]; ok && v != nil {
 			currentNode = v
 			continue
 		}
@@ -53,9 +61,69 @@ func (t *Trie) Insert(word string) *TrieNode {
 
 	// Lastly, when we are done finally, insert a last character to the last node.
 	currentNode.Children['*'] = nil
+	currentNode.Frequency++
 	return currentNode
 }
 
+// Add inserts a word. It's Insert without the returned node, so the Trie satisfies Dictionary.
+func (t *Trie) Add(word string) {
+	t.Insert(word)
+}
+
+// Contains returns true if the exact word was inserted. Unlike Search, a prefix of a word won't do.
+func (t *Trie) Contains(word string) bool {
+	node := t.Search(word)
+	return node != nil && node.isWord()
+}
+
+// Delete removes a word from the trie and prunes all the nodes which no longer lead to any word.
+// It returns false if the word wasn't in the trie.
+func (t *Trie) Delete(word string) bool {
+	// Keep track of the path so we can walk back up and prune.
+	path := []*TrieNode{t.Root}
+	runes := []rune(word)
+	currentNode := t.Root
+	for _, c := range runes {
+		v, ok := currentNode.Children[c]
+		if !ok || v == nil {
+			return false
+		}
+		currentNode = v
+		path = append(path, currentNode)
+	}
+
+	if !currentNode.isWord() {
+		return false
+	}
+	delete(currentNode.Children, '*')
+	currentNode.Frequency = 0
+
+	for i := len(path) - 1; i > 0; i-- {
+		if len(path[i].Children) > 0 {
+			break
+		}
+		delete(path[i-1].Children, runes[i-1])
+	}
+
+	return true
+}
+
+// NodeCount returns the number of nodes in the trie including the root. The end-of-word markers aren't
+// nodes, so they don't count.
+func (t *Trie) NodeCount() int {
+	return t.Root.count()
+}
+
+func (n *TrieNode) count() int {
+	c := 1
+	for _, child := range n.Children {
+		if child != nil {
+			c += child.count()
+		}
+	}
+	return c
+}
+
 // CollectAllWords will collect all available words from a given Child Node.
 // This is where it becomes handy that Search returns the last node.
 // Because we'll search from that node onward for all available words in our
@@ -86,20 +154,48 @@ func (t *Trie) AutoComplete(prefix string) []string {
 	return t.CollectAllWords("", currentNode, []string{})
 }
 
+// AutoCorrect returns the prefix if it's a word in the trie. Otherwise, it returns the closest word
+// within a few edits, preferring more frequent words on a tie. If no word is that close, it falls back
+// to completing the longest part of the prefix found in the trie with the shortest word below it. If
+// there is nothing to correct to, the prefix is returned unchanged.
 func (t *Trie) AutoCorrect(prefix string) string {
+	if t.Contains(prefix) {
+		return prefix
+	}
+
+	// Allowing about one edit per three characters keeps corrections sensible, and the search only
+	// visits the part of the trie within that distance.
+	maxDistance := utf8.RuneCountInString(prefix) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+	if matches := t.Fuzzy(prefix, maxDistance); len(matches) > 0 {
+		return matches[0].Word
+	}
+
 	node := t.Root
 	wordFoundSoFar := ""
 	for _, c := range prefix {
-		if v, ok := node.Children[c]; ok {
-			wordFoundSoFar += string(c)
-			node = v
-			continue
+		next, ok := node.Children[c]
+		if !ok || next == nil {
+			break
 		}
-
-		return wordFoundSoFar + t.CollectAllWords("", node, []string{})[0]
+		wordFoundSoFar += string(c)
+		node = next
 	}
 
-	return prefix
+	completions := t.CollectAllWords("", node, []string{})
+	if len(completions) == 0 {
+		return prefix
+	}
+	sort.Slice(completions, func(i, j int) bool {
+		a, b := utf8.RuneCountInString(completions[i]), utf8.RuneCountInString(completions[j])
+		if a != b {
+			return a < b
+		}
+		return completions[i] < completions[j]
+	})
+	return wordFoundSoFar + completions[0]
 }
 
 func Traverse(node *TrieNode, characters []rune) []rune {
@@ -17,7 +17,7 @@ func TestTrieSearch(t *testing.T) {
 	result := trie.Search("cat")
 	assert.Equal(t, &TrieNode{Children: map[rune]*TrieNode{
 		'*': nil,
-	}}, result)
+	}, Frequency: 1}, result)
 	result = trie.Search("nope")
 	assert.Nil(t, result)
 }
@@ -58,6 +58,39 @@ func TestTrieAutoCorrect(t *testing.T) {
 	assert.Equal(t, "catnap", result)
 	result = trie.AutoCorrect("calkka")
 	assert.Equal(t, "cat", result)
+	result = trie.AutoCorrect("cat")
+	assert.Equal(t, "cat", result)
+}
+
+func TestTrieAutoCorrectPrefix(t *testing.T) {
+	trie := NewTrie()
+	trie.Insert("cat")
+	trie.Insert("catnap")
+	trie.Insert("catnip")
+	trie.Insert("dog")
+	// These are prefixes of words, but not words themselves, so they still get corrected.
+	assert.Equal(t, "cat", trie.AutoCorrect("catn"))
+	assert.Equal(t, "catnap", trie.AutoCorrect("catna"))
+	assert.Equal(t, "dog", trie.AutoCorrect("do"))
+	// Nothing is within two edits, so the longest known prefix gets completed.
+	assert.Equal(t, "dog", trie.AutoCorrect("doxxxxx"))
+	assert.Equal(t, "cat", trie.AutoCorrect("*"))
+}
+
+func TestTrieAutoCorrectEndMarker(t *testing.T) {
+	trie := NewTrie()
+	trie.Insert("cat")
+	trie.Insert("catnapping")
+	// The '*' in the input is just a character which doesn't match anything, never the end-of-word marker.
+	assert.Equal(t, "cat", trie.AutoCorrect("cat*"))
+	assert.Equal(t, "cat", trie.AutoCorrect("cat*x"))
+	assert.Equal(t, "catnapping", trie.AutoCorrect("catnap*xxxxx"))
+}
+
+func TestTrieAutoCorrectEmpty(t *testing.T) {
+	trie := NewTrie()
+	result := trie.AutoCorrect("cat")
+	assert.Equal(t, "cat", result)
 }
 
 func TestTraverse(t *testing.T) {
@@ -68,3 +101,40 @@ func TestTraverse(t *testing.T) {
 	ch := Traverse(trie.Root, []rune{})
 	assert.Equal(t, "ace*bad*cat*", string(ch))
 }
+
+func TestTrieContains(t *testing.T) {
+	trie := NewTrie()
+	trie.Insert("bat")
+	trie.Insert("batter")
+	assert.True(t, trie.Contains("bat"))
+	assert.True(t, trie.Contains("batter"))
+	assert.False(t, trie.Contains("batt"))
+	assert.False(t, trie.Contains("cat"))
+}
+
+func TestTrieDelete(t *testing.T) {
+	trie := NewTrie()
+	trie.Insert("bat")
+	trie.Insert("batter")
+	trie.Insert("cat")
+	assert.False(t, trie.Delete("batt"))
+	assert.False(t, trie.Delete("dog"))
+
+	assert.True(t, trie.Delete("batter"))
+	assert.False(t, trie.Contains("batter"))
+	assert.True(t, trie.Contains("bat"))
+	// The "ter" branch is pruned.
+	assert.Equal(t, map[rune]*TrieNode{'*': nil}, trie.Search("bat").Children)
+
+	assert.False(t, trie.Delete("cat*"))
+	assert.False(t, trie.Delete("cat*x"))
+	assert.Nil(t, trie.Search("cat*"))
+	assert.True(t, trie.Contains("cat"))
+
+	// The root, "bat" and "cat".
+	assert.Equal(t, 7, trie.NodeCount())
+
+	assert.True(t, trie.Delete("bat"))
+	assert.Nil(t, trie.Search("b"))
+	assert.Equal(t, []string{"cat"}, trie.CollectAllWords("", nil, []string{}))
+}
@@ -0,0 +1,103 @@
+package chapter20
+
+// FenwickTree, also known as a binary indexed tree, keeps prefix sums of a list which keeps changing.
+// Both adding to a value and summing up a prefix take O(log n), using a lot less memory and code than
+// a SegmentTree. Every position i holds the sum of the values before it, going back as far as the
+// lowest set bit of i.
+type FenwickTree[T Number] struct {
+	// tree is indexed from 1, position i covering the values (i - i&-i, i].
+	tree []T
+}
+
+// NewFenwickTree creates a tree of n zeroes.
+func NewFenwickTree[T Number](n int) *FenwickTree[T] {
+	return &FenwickTree[T]{tree: make([]T, n+1)}
+}
+
+// NewFenwickTreeFrom builds a tree over values in O(n).
+func NewFenwickTreeFrom[T Number](values []T) *FenwickTree[T] {
+	f := &FenwickTree[T]{tree: make([]T, len(values)+1)}
+	copy(f.tree[1:], values)
+	for i := 1; i < len(f.tree); i++ {
+		if parent := i + i&-i; parent < len(f.tree) {
+			f.tree[parent] += f.tree[i]
+		}
+	}
+	return f
+}
+
+// Len returns the number of values in the tree.
+func (f *FenwickTree[T]) Len() int {
+	return len(f.tree) - 1
+}
+
+// Add adds delta to the value at index.
+func (f *FenwickTree[T]) Add(index int, delta T) error {
+	if err := checkIndex(index, f.Len()); err != nil {
+		return err
+	}
+	for i := index + 1; i < len(f.tree); i += i & -i {
+		f.tree[i] += delta
+	}
+	return nil
+}
+
+// Set replaces the value at index.
+func (f *FenwickTree[T]) Set(index int, value T) error {
+	current, err := f.Get(index)
+	if err != nil {
+		return err
+	}
+	return f.Add(index, value-current)
+}
+
+// Get returns the value at index.
+func (f *FenwickTree[T]) Get(index int) (T, error) {
+	return f.RangeSum(index, index+1)
+}
+
+// PrefixSum returns the sum of the first count values.
+func (f *FenwickTree[T]) PrefixSum(count int) (T, error) {
+	var sum T
+	if err := checkIndex(count, f.Len()+1); err != nil {
+		return sum, err
+	}
+	for i := count; i > 0; i -= i & -i {
+		sum += f.tree[i]
+	}
+	return sum, nil
+}
+
+// RangeSum returns the sum of the values from index from up to but not including index to.
+func (f *FenwickTree[T]) RangeSum(from, to int) (T, error) {
+	var sum T
+	if err := checkRange(from, to, f.Len()); err != nil {
+		return sum, err
+	}
+	// Both can't fail anymore.
+	upper, _ := f.PrefixSum(to)
+	lower, _ := f.PrefixSum(from)
+	return upper - lower, nil
+}
+
+// Search returns the smallest count for which the sum of the first count values is at least target,
+// or Len+1 if there is none. It only works if no value is negative, so the prefix sums never decrease.
+func (f *FenwickTree[T]) Search(target T) int {
+	if target <= 0 {
+		return 0
+	}
+	step := 1
+	for step*2 < len(f.tree) {
+		step *= 2
+	}
+
+	// Find the largest position whose prefix sum is still below target, one bit at a time.
+	position := 0
+	for ; step > 0; step /= 2 {
+		if next := position + step; next < len(f.tree) && f.tree[next] < target {
+			position = next
+			target -= f.tree[next]
+		}
+	}
+	return position + 1
+}
@@ -0,0 +1,68 @@
+package chapter20
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFenwickTree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	values := make([]int, 41)
+	for i := range values {
+		values[i] = r.Intn(100) - 50
+	}
+	f := NewFenwickTreeFrom(values)
+	values = append([]int{}, values...)
+	assert.Equal(t, 41, f.Len())
+
+	for i := 0; i < 500; i++ {
+		index := r.Intn(len(values))
+		if i%2 == 0 {
+			delta := r.Intn(100) - 50
+			require.NoError(t, f.Add(index, delta))
+			values[index] += delta
+		} else {
+			require.NoError(t, f.Set(index, i))
+			values[index] = i
+		}
+
+		from := r.Intn(len(values))
+		to := from + 1 + r.Intn(len(values)-from)
+		got, err := f.RangeSum(from, to)
+		require.NoError(t, err)
+		assert.Equal(t, naiveQuery(values, from, to, Add[int]), got)
+	}
+
+	sum, err := f.PrefixSum(0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sum)
+	_, err = f.PrefixSum(42)
+	assert.EqualError(t, err, "index 42 is out of range [0, 42)")
+	assert.Error(t, f.Add(-1, 1))
+}
+
+func TestFenwickTreeBuild(t *testing.T) {
+	values := []float64{1.5, 2, 0, 4, 0.5}
+	built := NewFenwickTreeFrom(values)
+	added := NewFenwickTree[float64](len(values))
+	for i, v := range values {
+		require.NoError(t, added.Add(i, v))
+	}
+	assert.Equal(t, added, built)
+}
+
+func TestFenwickTreeSearch(t *testing.T) {
+	f := NewFenwickTreeFrom([]int{3, 0, 2, 5, 1, 0, 4})
+	// The prefix sums are 3, 3, 5, 10, 11, 11, 15.
+	assert.Equal(t, 0, f.Search(0))
+	assert.Equal(t, 1, f.Search(1))
+	assert.Equal(t, 1, f.Search(3))
+	assert.Equal(t, 3, f.Search(4))
+	assert.Equal(t, 4, f.Search(6))
+	assert.Equal(t, 5, f.Search(11))
+	assert.Equal(t, 7, f.Search(15))
+	assert.Equal(t, 8, f.Search(16))
+}
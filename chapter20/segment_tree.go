@@ -0,0 +1,320 @@
+package chapter20
+
+import "fmt"
+
+// Number covers all numeric types.
+type Number interface {
+	int | int8 | int16 | int32 | int64 | float32 | float64
+}
+
+// Add, Min and Max are the usual combine functions for a SegmentTree.
+func Add[T Number](a, b T) T {
+	return a + b
+}
+
+func Min[T Number](a, b T) T {
+	if b < a {
+		return b
+	}
+	return a
+}
+
+func Max[T Number](a, b T) T {
+	if b > a {
+		return b
+	}
+	return a
+}
+
+func checkRange(from, to, length int) error {
+	if from < 0 || to > length || from >= to {
+		return fmt.Errorf("range [%d, %d) is not a non-empty range within [0, %d)", from, to, length)
+	}
+	return nil
+}
+
+func checkIndex(index, length int) error {
+	if index < 0 || index >= length {
+		return fmt.Errorf("index %d is out of range [0, %d)", index, length)
+	}
+	return nil
+}
+
+// SegmentTree answers queries over any range of a list, like the sum or the minimum of the range,
+// in O(log n) while the values in the list keep changing. Each node holds the combined value of a
+// section of the list, and a range is covered by at most two nodes per level.
+//
+// The combine function has to be associative, but it doesn't need to be commutative; values are
+// always combined left to right.
+type SegmentTree[T any] struct {
+	// tree holds the leaves at n..2n-1 and every parent i at i, with children at 2i and 2i+1.
+	tree    []T
+	n       int
+	combine func(a, b T) T
+}
+
+// NewSegmentTree builds a tree over values in O(n).
+func NewSegmentTree[T any](values []T, combine func(a, b T) T) *SegmentTree[T] {
+	n := len(values)
+	s := &SegmentTree[T]{tree: make([]T, 2*n), n: n, combine: combine}
+	copy(s.tree[n:], values)
+	for i := n - 1; i > 0; i-- {
+		s.tree[i] = combine(s.tree[2*i], s.tree[2*i+1])
+	}
+	return s
+}
+
+// Len returns the number of values in the tree.
+func (s *SegmentTree[T]) Len() int {
+	return s.n
+}
+
+// Get returns the value at index.
+func (s *SegmentTree[T]) Get(index int) (T, error) {
+	if err := checkIndex(index, s.n); err != nil {
+		var v T
+		return v, err
+	}
+	return s.tree[s.n+index], nil
+}
+
+// Set replaces the value at index.
+func (s *SegmentTree[T]) Set(index int, value T) error {
+	if err := checkIndex(index, s.n); err != nil {
+		return err
+	}
+	i := s.n + index
+	s.tree[i] = value
+	for i /= 2; i > 0; i /= 2 {
+		s.tree[i] = s.combine(s.tree[2*i], s.tree[2*i+1])
+	}
+	return nil
+}
+
+// Query combines the values from index from up to but not including index to.
+func (s *SegmentTree[T]) Query(from, to int) (T, error) {
+	var left, right T
+	if err := checkRange(from, to, s.n); err != nil {
+		return left, err
+	}
+
+	// Walk up from both ends, collecting the nodes which are only partly covered by their parent.
+	// The left side collects in order, the right side in reverse, so both are kept apart.
+	hasLeft, hasRight := false, false
+	for l, r := from+s.n, to+s.n; l < r; l, r = l/2, r/2 {
+		if l&1 == 1 {
+			if hasLeft {
+				left = s.combine(left, s.tree[l])
+			} else {
+				left, hasLeft = s.tree[l], true
+			}
+			l++
+		}
+		if r&1 == 1 {
+			r--
+			if hasRight {
+				right = s.combine(s.tree[r], right)
+			} else {
+				right, hasRight = s.tree[r], true
+			}
+		}
+	}
+
+	switch {
+	case !hasLeft:
+		return right, nil
+	case !hasRight:
+		return left, nil
+	}
+	return s.combine(left, right), nil
+}
+
+// LazyOps describes how a LazySegmentTree combines values of type T and applies updates of type U.
+type LazyOps[T, U any] struct {
+	// Combine joins the values of two neighbouring sections. It has to be associative.
+	Combine func(a, b T) T
+	// Apply returns the combined value of a section of length values after update is applied to
+	// every value in it.
+	Apply func(value T, update U, length int) T
+	// Compose returns a single update which has the same effect as applying first and then second.
+	Compose func(first, second U) U
+}
+
+// RangeAddSum keeps sums and adds to every value of a range.
+func RangeAddSum[T Number]() LazyOps[T, T] {
+	return LazyOps[T, T]{
+		Combine: Add[T],
+		Apply: func(value, update T, length int) T {
+			return value + update*T(length)
+		},
+		Compose: Add[T],
+	}
+}
+
+// RangeAddMin keeps minimums and adds to every value of a range.
+func RangeAddMin[T Number]() LazyOps[T, T] {
+	return LazyOps[T, T]{
+		Combine: Min[T],
+		Apply: func(value, update T, _ int) T {
+			return value + update
+		},
+		Compose: Add[T],
+	}
+}
+
+// RangeAddMax keeps maximums and adds to every value of a range.
+func RangeAddMax[T Number]() LazyOps[T, T] {
+	return LazyOps[T, T]{
+		Combine: Max[T],
+		Apply: func(value, update T, _ int) T {
+			return value + update
+		},
+		Compose: Add[T],
+	}
+}
+
+// LazySegmentTree is a segment tree which can also update a whole range of values in O(log n).
+// An update of a range stops at the nodes covering it and is only pushed further down when a later
+// query or update needs to look inside those nodes.
+type LazySegmentTree[T, U any] struct {
+	tree []T
+	// lazy holds the updates which are applied to a node but not to its children yet.
+	lazy    []U
+	pending []bool
+	n       int
+	ops     LazyOps[T, U]
+}
+
+// NewLazySegmentTree builds a tree over values in O(n).
+func NewLazySegmentTree[T, U any](values []T, ops LazyOps[T, U]) *LazySegmentTree[T, U] {
+	n := len(values)
+	s := &LazySegmentTree[T, U]{
+		tree:    make([]T, 4*n),
+		lazy:    make([]U, 4*n),
+		pending: make([]bool, 4*n),
+		n:       n,
+		ops:     ops,
+	}
+	if n > 0 {
+		s.build(values, 1, 0, n)
+	}
+	return s
+}
+
+func (s *LazySegmentTree[T, U]) build(values []T, node, lo, hi int) {
+	if hi-lo == 1 {
+		s.tree[node] = values[lo]
+		return
+	}
+	mid := (lo + hi) / 2
+	s.build(values, 2*node, lo, mid)
+	s.build(values, 2*node+1, mid, hi)
+	s.tree[node] = s.ops.Combine(s.tree[2*node], s.tree[2*node+1])
+}
+
+// apply updates the node covering [lo, hi) and remembers the update for its children.
+func (s *LazySegmentTree[T, U]) apply(node, lo, hi int, update U) {
+	s.tree[node] = s.ops.Apply(s.tree[node], update, hi-lo)
+	if hi-lo == 1 {
+		return
+	}
+	if s.pending[node] {
+		s.lazy[node] = s.ops.Compose(s.lazy[node], update)
+	} else {
+		s.lazy[node], s.pending[node] = update, true
+	}
+}
+
+func (s *LazySegmentTree[T, U]) push(node, lo, hi int) {
+	if !s.pending[node] {
+		return
+	}
+	mid := (lo + hi) / 2
+	s.apply(2*node, lo, mid, s.lazy[node])
+	s.apply(2*node+1, mid, hi, s.lazy[node])
+	var zero U
+	s.lazy[node], s.pending[node] = zero, false
+}
+
+// Len returns the number of values in the tree.
+func (s *LazySegmentTree[T, U]) Len() int {
+	return s.n
+}
+
+// Get returns the value at index.
+func (s *LazySegmentTree[T, U]) Get(index int) (T, error) {
+	return s.Query(index, index+1)
+}
+
+// Set replaces the value at index.
+func (s *LazySegmentTree[T, U]) Set(index int, value T) error {
+	if err := checkIndex(index, s.n); err != nil {
+		return err
+	}
+	s.set(1, 0, s.n, index, value)
+	return nil
+}
+
+func (s *LazySegmentTree[T, U]) set(node, lo, hi, index int, value T) {
+	if hi-lo == 1 {
+		s.tree[node] = value
+		return
+	}
+	s.push(node, lo, hi)
+	mid := (lo + hi) / 2
+	if index < mid {
+		s.set(2*node, lo, mid, index, value)
+	} else {
+		s.set(2*node+1, mid, hi, index, value)
+	}
+	s.tree[node] = s.ops.Combine(s.tree[2*node], s.tree[2*node+1])
+}
+
+// Update applies update to every value from index from up to but not including index to.
+func (s *LazySegmentTree[T, U]) Update(from, to int, update U) error {
+	if err := checkRange(from, to, s.n); err != nil {
+		return err
+	}
+	s.update(1, 0, s.n, from, to, update)
+	return nil
+}
+
+func (s *LazySegmentTree[T, U]) update(node, lo, hi, from, to int, update U) {
+	if from <= lo && hi <= to {
+		s.apply(node, lo, hi, update)
+		return
+	}
+	s.push(node, lo, hi)
+	mid := (lo + hi) / 2
+	if from < mid {
+		s.update(2*node, lo, mid, from, to, update)
+	}
+	if to > mid {
+		s.update(2*node+1, mid, hi, from, to, update)
+	}
+	s.tree[node] = s.ops.Combine(s.tree[2*node], s.tree[2*node+1])
+}
+
+// Query combines the values from index from up to but not including index to.
+func (s *LazySegmentTree[T, U]) Query(from, to int) (T, error) {
+	if err := checkRange(from, to, s.n); err != nil {
+		var v T
+		return v, err
+	}
+	return s.query(1, 0, s.n, from, to), nil
+}
+
+func (s *LazySegmentTree[T, U]) query(node, lo, hi, from, to int) T {
+	if from <= lo && hi <= to {
+		return s.tree[node]
+	}
+	s.push(node, lo, hi)
+	mid := (lo + hi) / 2
+	switch {
+	case to <= mid:
+		return s.query(2*node, lo, mid, from, to)
+	case from >= mid:
+		return s.query(2*node+1, mid, hi, from, to)
+	}
+	return s.ops.Combine(s.query(2*node, lo, mid, from, to), s.query(2*node+1, mid, hi, from, to))
+}
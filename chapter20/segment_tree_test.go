@@ -0,0 +1,164 @@
+package chapter20
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func naiveQuery(values []int, from, to int, combine func(a, b int) int) int {
+	result := values[from]
+	for _, v := range values[from+1 : to] {
+		result = combine(result, v)
+	}
+	return result
+}
+
+func TestSegmentTree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	values := make([]int, 37)
+	for i := range values {
+		values[i] = r.Intn(200) - 100
+	}
+
+	for name, combine := range map[string]func(a, b int) int{"sum": Add[int], "min": Min[int], "max": Max[int]} {
+		t.Run(name, func(t *testing.T) {
+			values := append([]int{}, values...)
+			s := NewSegmentTree(values, combine)
+			for i := 0; i < 500; i++ {
+				if i%3 == 0 {
+					index, v := r.Intn(len(values)), r.Intn(200)-100
+					require.NoError(t, s.Set(index, v))
+					values[index] = v
+				}
+				from := r.Intn(len(values))
+				to := from + 1 + r.Intn(len(values)-from)
+				got, err := s.Query(from, to)
+				require.NoError(t, err)
+				assert.Equal(t, naiveQuery(values, from, to, combine), got, "[%d, %d)", from, to)
+			}
+		})
+	}
+}
+
+func TestSegmentTreeOrder(t *testing.T) {
+	// Concatenation isn't commutative, so this checks the values are combined left to right.
+	s := NewSegmentTree([]string{"a", "b", "c", "d", "e", "f", "g"}, func(a, b string) string { return a + b })
+	for from := 0; from < 7; from++ {
+		for to := from + 1; to <= 7; to++ {
+			got, err := s.Query(from, to)
+			require.NoError(t, err)
+			assert.Equal(t, "abcdefg"[from:to], got)
+		}
+	}
+
+	_, err := s.Query(3, 3)
+	assert.EqualError(t, err, "range [3, 3) is not a non-empty range within [0, 7)")
+	_, err = s.Query(0, 8)
+	assert.Error(t, err)
+	assert.EqualError(t, s.Set(7, "h"), "index 7 is out of range [0, 7)")
+	v, err := s.Get(2)
+	require.NoError(t, err)
+	assert.Equal(t, "c", v)
+}
+
+func TestLazySegmentTree(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	tests := map[string]struct {
+		ops     LazyOps[int, int]
+		combine func(a, b int) int
+	}{
+		"sum": {ops: RangeAddSum[int](), combine: Add[int]},
+		"min": {ops: RangeAddMin[int](), combine: Min[int]},
+		"max": {ops: RangeAddMax[int](), combine: Max[int]},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			values := make([]int, 53)
+			for i := range values {
+				values[i] = r.Intn(100)
+			}
+			s := NewLazySegmentTree(values, tt.ops)
+			values = append([]int{}, values...)
+
+			for i := 0; i < 1000; i++ {
+				from := r.Intn(len(values))
+				to := from + 1 + r.Intn(len(values)-from)
+				switch r.Intn(3) {
+				case 0:
+					delta := r.Intn(21) - 10
+					require.NoError(t, s.Update(from, to, delta))
+					for j := from; j < to; j++ {
+						values[j] += delta
+					}
+				case 1:
+					v := r.Intn(100)
+					require.NoError(t, s.Set(from, v))
+					values[from] = v
+				default:
+					got, err := s.Query(from, to)
+					require.NoError(t, err)
+					assert.Equal(t, naiveQuery(values, from, to, tt.combine), got, "[%d, %d)", from, to)
+				}
+			}
+			for i, want := range values {
+				got, err := s.Get(i)
+				require.NoError(t, err)
+				assert.Equal(t, want, got)
+			}
+		})
+	}
+}
+
+func TestLazySegmentTreeAssign(t *testing.T) {
+	// Assigning a value to a range, where a later assignment overrides an earlier one.
+	ops := LazyOps[int, int]{
+		Combine: Add[int],
+		Apply: func(_, update, length int) int {
+			return update * length
+		},
+		Compose: func(_, second int) int {
+			return second
+		},
+	}
+	s := NewLazySegmentTree([]int{1, 2, 3, 4, 5, 6, 7, 8}, ops)
+	require.NoError(t, s.Update(0, 6, 10))
+	require.NoError(t, s.Update(2, 8, 1))
+	sum, err := s.Query(0, 8)
+	require.NoError(t, err)
+	assert.Equal(t, 26, sum)
+	sum, err = s.Query(1, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 11, sum)
+
+	empty := NewLazySegmentTree(nil, ops)
+	assert.Equal(t, 0, empty.Len())
+	assert.Error(t, empty.Update(0, 1, 1))
+}
+
+func BenchmarkRangeSum(b *testing.B) {
+	values := make([]int, 100_000)
+	for i := range values {
+		values[i] = i
+	}
+	b.Run("naive", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			naiveQuery(values, 100, len(values)-100, Add[int])
+		}
+	})
+	b.Run("segment tree", func(b *testing.B) {
+		s := NewSegmentTree(values, Add[int])
+		for i := 0; i < b.N; i++ {
+			_, _ = s.Query(100, len(values)-100)
+		}
+	})
+	b.Run("fenwick tree", func(b *testing.B) {
+		f := NewFenwickTreeFrom(values)
+		for i := 0; i < b.N; i++ {
+			_, _ = f.RangeSum(100, len(values)-100)
+		}
+	})
+}